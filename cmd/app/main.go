@@ -7,14 +7,21 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/daaser/server/internal/calc"
+	"github.com/daaser/server/internal/digest"
 	"github.com/daaser/server/internal/fib"
 	"github.com/daaser/server/internal/header"
+	"github.com/daaser/server/internal/id"
 	"github.com/daaser/server/internal/ip"
+	"github.com/daaser/server/internal/job"
 	"github.com/daaser/server/internal/log"
+	"github.com/daaser/server/internal/numtheory"
+	"github.com/daaser/server/internal/random"
 	"github.com/daaser/server/internal/str"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/gorilla/mux"
@@ -39,6 +46,20 @@ func main() {
 		)
 		http2 = flag.Bool("http2", false, "Use HTTP/2")
 		debug = flag.Bool("debug", false, "Debug logging")
+
+		fibCache = flag.Int("fib.cache", 64<<20, "Bytes of Fibonacci results to cache")
+		fibSpan  = flag.Uint64("fib.span", 10000, "Maximum number of values in a Fibonacci range")
+		fibMax   = flag.Uint64("fib.max", 10000000, "Largest Fibonacci index to compute")
+
+		calcTimeout = flag.Duration("calc.timeout", 2*time.Second, "Longest time an expression may take to evaluate")
+		mathBudget  = flag.Duration("math.budget", 5*time.Second, "Longest time a factorization may be given")
+
+		jobWorkers = flag.Int("job.workers", runtime.NumCPU(), "Number of jobs to run at once")
+		jobQueue   = flag.Int("job.queue", 64, "Number of jobs that may wait for a worker")
+		jobTimeout = flag.Duration("job.timeout", 10*time.Minute, "Time after which a running job is cancelled")
+		jobTTL     = flag.Duration("job.ttl", 10*time.Minute, "Time to keep finished job results")
+		jobResults = flag.Int("job.results", 1000, "Number of finished job results to keep")
+		jobFibMax  = flag.Uint64("job.fib.max", 200000000, "Largest Fibonacci index to compute in a job")
 	)
 
 	flag.Parse()
@@ -74,7 +95,26 @@ func main() {
 
 	var fs fib.Service
 	{
-		fs = fib.NewService()
+		fs = fib.NewService(
+			fib.Config{
+				CacheBytes: *fibCache,
+				MaxSpan:    *fibSpan,
+				MaxN:       *fibMax,
+				MaxJobN:    *jobFibMax,
+			},
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "fib",
+				Name:      "cache_hits",
+				Help:      "Number of Fibonacci results served from the cache.",
+			}, []string{}),
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "fib",
+				Name:      "cache_misses",
+				Help:      "Number of Fibonacci results computed.",
+			}, []string{}),
+		)
 		fs = fib.LoggingMiddleware(*logger)(fs)
 		fs = fib.NewInstrumentingMiddleware(
 			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
@@ -114,6 +154,101 @@ func main() {
 		)
 	}
 
+	var cs calc.Service
+	{
+		cs = calc.NewService(calc.Config{Timeout: *calcTimeout}, fs)
+		cs = calc.LoggingMiddleware(*logger)(cs)
+		cs = calc.NewInstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "calc",
+				Name:      "request_count",
+				Help:      "Number of requests received.",
+			}, fieldKeys),
+			kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+				Namespace: "api",
+				Subsystem: "calc",
+				Name:      "request_latency_microseconds",
+				Help:      "Total duration of requests in microseconds.",
+			}, fieldKeys),
+			cs,
+		)
+	}
+
+	var ms numtheory.Service
+	{
+		ms = numtheory.NewService(numtheory.Config{MaxBudget: *mathBudget})
+		ms = numtheory.LoggingMiddleware(*logger)(ms)
+		ms = numtheory.NewInstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "math",
+				Name:      "request_count",
+				Help:      "Number of requests received.",
+			}, fieldKeys),
+			kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+				Namespace: "api",
+				Subsystem: "math",
+				Name:      "request_latency_microseconds",
+				Help:      "Total duration of requests in microseconds.",
+			}, fieldKeys),
+			ms,
+		)
+	}
+
+	var js job.Service
+	{
+		js = job.NewService(
+			job.Config{
+				Workers:     *jobWorkers,
+				QueueLength: *jobQueue,
+				Timeout:     *jobTimeout,
+				ResultTTL:   *jobTTL,
+				MaxResults:  *jobResults,
+			},
+			map[string]job.Kind{
+				"fib": fib.NewJob(fs, *jobFibMax),
+			},
+		)
+		js = job.LoggingMiddleware(*logger)(js)
+		js = job.NewInstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "job",
+				Name:      "request_count",
+				Help:      "Number of requests received.",
+			}, fieldKeys),
+			kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+				Namespace: "api",
+				Subsystem: "job",
+				Name:      "request_latency_microseconds",
+				Help:      "Total duration of requests in microseconds.",
+			}, fieldKeys),
+			js,
+		)
+	}
+
+	var ids id.Service
+	{
+		ids = id.NewService()
+		ids = id.LoggingMiddleware(*logger)(ids)
+		ids = id.NewInstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "id",
+				Name:      "request_count",
+				Help:      "Number of requests received.",
+			}, fieldKeys),
+			kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+				Namespace: "api",
+				Subsystem: "id",
+				Name:      "request_latency_microseconds",
+				Help:      "Total duration of requests in microseconds.",
+			}, fieldKeys),
+			ids,
+		)
+	}
+
 	var is ip.Service
 	{
 		is = ip.NewService()
@@ -135,14 +270,63 @@ func main() {
 		)
 	}
 
+	var ds digest.Service
+	{
+		ds = digest.NewService()
+		ds = digest.LoggingMiddleware(*logger)(ds)
+		ds = digest.NewInstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "digest",
+				Name:      "request_count",
+				Help:      "Number of requests received.",
+			}, fieldKeys),
+			kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+				Namespace: "api",
+				Subsystem: "digest",
+				Name:      "request_latency_microseconds",
+				Help:      "Total duration of requests in microseconds.",
+			}, fieldKeys),
+			ds,
+		)
+	}
+
+	var rs random.Service
+	{
+		rs = random.NewService()
+		rs = random.LoggingMiddleware(*logger)(rs)
+		rs = random.NewInstrumentingMiddleware(
+			kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+				Namespace: "api",
+				Subsystem: "random",
+				Name:      "request_count",
+				Help:      "Number of requests received.",
+			}, fieldKeys),
+			kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+				Namespace: "api",
+				Subsystem: "random",
+				Name:      "request_latency_microseconds",
+				Help:      "Total duration of requests in microseconds.",
+			}, fieldKeys),
+			rs,
+		)
+	}
+
 	r := mux.NewRouter()
 
 	// our main API routes
 	// the methods for these are defined in their respective handlers
 	r.PathPrefix("/string").Handler(str.MakeHandler(ss))
 	r.PathPrefix("/fib").Handler(fib.MakeHandler(fs))
+	r.PathPrefix("/seq").Handler(fib.MakeHandler(fs))
+	r.PathPrefix("/math").Handler(numtheory.MakeHandler(ms))
+	r.Path("/calc").Handler(calc.MakeHandler(cs))
+	r.PathPrefix("/id").Handler(id.MakeHandler(ids))
 	r.Path("/headers").Handler(header.MakeHandler(hs))
 	r.Path("/ip").Handler(ip.MakeHandler(is))
+	r.Path("/digest").Handler(digest.MakeHandler(ds))
+	r.PathPrefix("/random").Handler(random.MakeHandler(rs))
+	r.PathPrefix("/jobs").Handler(job.MakeHandler(js))
 
 	// expose the Promethus metrics we registered above
 	r.Handle("/metrics", promhttp.Handler())
@@ -203,7 +387,7 @@ func main() {
 	}()
 
 	go func() {
-		c := make(chan os.Signal)
+		c := make(chan os.Signal, 1)
 		signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
 		errs <- fmt.Errorf("%s", <-c)
 	}()
@@ -220,8 +404,8 @@ func main() {
 func accessControl(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, X-HMAC-Key")
 
 		if r.Method == "OPTIONS" {
 			return
@@ -255,7 +439,7 @@ func walkRoute(r *mux.Router) error {
 			if err == nil {
 				fmt.Println("Path regexp:", pathRegexp)
 			}
-			fmt.Println("Methods:", strings.Join(methods, ","), "\n")
+			fmt.Printf("Methods: %s\n\n", strings.Join(methods, ","))
 		}
 		return nil
 	})
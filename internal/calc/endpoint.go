@@ -0,0 +1,26 @@
+package calc
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type calcRequest struct {
+	Expr      string `json:"expr"`
+	Precision int    `json:"precision,omitempty"`
+}
+
+// defaultPrecision applies when a request leaves the precision out.
+const defaultPrecision = 30
+
+func makeCalcEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(calcRequest)
+		precision := req.Precision
+		if precision == 0 {
+			precision = defaultPrecision
+		}
+		return svc.Evaluate(ctx, req.Expr, precision)
+	}
+}
@@ -0,0 +1,316 @@
+package calc
+
+import (
+	"context"
+	"math"
+	"math/big"
+
+	"github.com/daaser/server/internal/fib"
+)
+
+const (
+	// maxBits bounds the numerator plus denominator of every exact value,
+	// and so the size of the integers they are computed from.
+	maxBits = 1 << 16
+	// maxDigits bounds the digits and exponent of literals so that they fit
+	// within maxBits.
+	maxDigits = 19000
+)
+
+var functions = map[string]int{
+	"sqrt":  1,
+	"abs":   1,
+	"fact":  1,
+	"binom": 2,
+	"fib":   1,
+}
+
+// value is an exact rational until an operation such as sqrt leaves the
+// rationals, after which it is carried as a float of the chosen precision.
+type value struct {
+	r *big.Rat
+	f *big.Float
+}
+
+func (v value) exact() bool {
+	return v.f == nil
+}
+
+type evaluator struct {
+	ctx  context.Context
+	prec uint
+	fib  fib.Service
+}
+
+func (e *evaluator) float(v value) *big.Float {
+	if v.exact() {
+		return new(big.Float).SetPrec(e.prec).SetRat(v.r)
+	}
+	return v.f
+}
+
+func (e *evaluator) newFloat() *big.Float {
+	return new(big.Float).SetPrec(e.prec)
+}
+
+func checkSize(col int, r *big.Rat) error {
+	if r.Num().BitLen()+r.Denom().BitLen() > maxBits {
+		return errorf(col, "result too large")
+	}
+	return nil
+}
+
+// integer returns v as an int64 if it is an exact integer that fits.
+func integer(col int, v value, what string) (int64, error) {
+	if !v.exact() || !v.r.IsInt() || !v.r.Num().IsInt64() {
+		return 0, errorf(col, "%s must be an integer", what)
+	}
+	return v.r.Num().Int64(), nil
+}
+
+func (e *evaluator) eval(n *node) (value, error) {
+	if err := e.ctx.Err(); err != nil {
+		return value{}, err
+	}
+	if n.op == "num" {
+		return value{r: n.num}, nil
+	}
+	args := make([]value, len(n.args))
+	for i, a := range n.args {
+		v, err := e.eval(a)
+		if err != nil {
+			return value{}, err
+		}
+		args[i] = v
+	}
+
+	v, err := e.apply(n, args)
+	if err != nil {
+		return value{}, err
+	}
+	if v.exact() {
+		if err := checkSize(n.col, v.r); err != nil {
+			return value{}, err
+		}
+	} else if v.f.IsInf() {
+		return value{}, errorf(n.col, "result too large")
+	}
+	return v, nil
+}
+
+func (e *evaluator) apply(n *node, args []value) (value, error) {
+	switch n.op {
+	case "neg":
+		if args[0].exact() {
+			return value{r: new(big.Rat).Neg(args[0].r)}, nil
+		}
+		return value{f: e.newFloat().Neg(args[0].f)}, nil
+	case "abs":
+		if args[0].exact() {
+			return value{r: new(big.Rat).Abs(args[0].r)}, nil
+		}
+		return value{f: e.newFloat().Abs(args[0].f)}, nil
+	case "+", "-", "*":
+		return e.arith(n.op, args[0], args[1]), nil
+	case "/":
+		if isZero(args[1]) {
+			return value{}, errorf(n.col, "division by zero")
+		}
+		return e.arith(n.op, args[0], args[1]), nil
+	case "%":
+		if isZero(args[1]) {
+			return value{}, errorf(n.col, "division by zero")
+		}
+		return e.mod(n.col, args[0], args[1])
+	case "^":
+		return e.pow(n.col, args[0], args[1])
+	case "sqrt":
+		return e.sqrt(n.col, args[0])
+	case "fact":
+		return e.fact(n.col, args[0])
+	case "binom":
+		return e.binom(n.col, args[0], args[1])
+	case "fib":
+		return e.fibonacci(n.col, args[0])
+	}
+	return value{}, errorf(n.col, "unknown operator %q", n.op)
+}
+
+func isZero(v value) bool {
+	if v.exact() {
+		return v.r.Sign() == 0
+	}
+	return v.f.Sign() == 0
+}
+
+func (e *evaluator) arith(op string, a, b value) value {
+	if a.exact() && b.exact() {
+		r := new(big.Rat)
+		switch op {
+		case "+":
+			r.Add(a.r, b.r)
+		case "-":
+			r.Sub(a.r, b.r)
+		case "*":
+			r.Mul(a.r, b.r)
+		case "/":
+			r.Quo(a.r, b.r)
+		}
+		return value{r: r}
+	}
+	f, x, y := e.newFloat(), e.float(a), e.float(b)
+	switch op {
+	case "+":
+		f.Add(x, y)
+	case "-":
+		f.Sub(x, y)
+	case "*":
+		f.Mul(x, y)
+	case "/":
+		f.Quo(x, y)
+	}
+	return value{f: f}
+}
+
+// mod returns a - b·floor(a/b), which takes the sign of b.
+func (e *evaluator) mod(col int, a, b value) (value, error) {
+	if a.exact() && b.exact() {
+		q := new(big.Rat).Quo(a.r, b.r)
+		// denominators are positive, so Euclidean division floors
+		fl := new(big.Int).Div(q.Num(), q.Denom())
+		r := new(big.Rat).Mul(b.r, new(big.Rat).SetInt(fl))
+		return value{r: r.Sub(a.r, r)}, nil
+	}
+	x, y := e.float(a), e.float(b)
+	q := e.newFloat().Quo(x, y)
+	// an infinite quotient has no integer part, and a huge one would be
+	// expanded into an integer of that many bits
+	if q.IsInf() || q.MantExp(nil) > maxBits {
+		return value{}, errorf(col, "result too large")
+	}
+	fl, _ := q.Int(nil)
+	if q.Sign() < 0 && !q.IsInt() {
+		fl.Sub(fl, big.NewInt(1))
+	}
+	r := e.newFloat().Mul(y, e.newFloat().SetInt(fl))
+	return value{f: r.Sub(x, r)}, nil
+}
+
+// pow raises a to an integer power by repeated squaring, checking first
+// that an exact result would fit.
+func (e *evaluator) pow(col int, a, b value) (value, error) {
+	exp, err := integer(col, b, "exponent")
+	if err != nil {
+		return value{}, err
+	}
+	neg := exp < 0
+	if neg {
+		if isZero(a) {
+			return value{}, errorf(col, "division by zero")
+		}
+		exp = -exp
+	}
+
+	if a.exact() {
+		// about log2 of the numerator and denominator, so 1 and -1 pass
+		bits := a.r.Num().BitLen() - 1 + a.r.Denom().BitLen() - 1
+		if bits > 0 && float64(bits)*float64(exp) > maxBits {
+			return value{}, errorf(col, "result too large")
+		}
+		e := big.NewInt(exp)
+		num := new(big.Int).Exp(a.r.Num(), e, nil)
+		den := new(big.Int).Exp(a.r.Denom(), e, nil)
+		if neg {
+			num, den = den, num
+		}
+		return value{r: new(big.Rat).SetFrac(num, den)}, nil
+	}
+
+	result, sq := e.newFloat().SetInt64(1), e.newFloat().Set(a.f)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result.Mul(result, sq)
+		}
+		sq.Mul(sq, sq)
+	}
+	if neg {
+		result.Quo(e.newFloat().SetInt64(1), result)
+	}
+	return value{f: result}, nil
+}
+
+// sqrt stays exact for squares of rationals.
+func (e *evaluator) sqrt(col int, a value) (value, error) {
+	if (a.exact() && a.r.Sign() < 0) || (!a.exact() && a.f.Sign() < 0) {
+		return value{}, errorf(col, "square root of a negative number")
+	}
+	if a.exact() {
+		num := new(big.Int).Sqrt(a.r.Num())
+		den := new(big.Int).Sqrt(a.r.Denom())
+		if new(big.Int).Mul(num, num).Cmp(a.r.Num()) == 0 &&
+			new(big.Int).Mul(den, den).Cmp(a.r.Denom()) == 0 {
+			return value{r: new(big.Rat).SetFrac(num, den)}, nil
+		}
+	}
+	return value{f: e.newFloat().Sqrt(e.float(a))}, nil
+}
+
+// log2Fact returns log2(n!) from the log gamma function.
+func log2Fact(n int64) float64 {
+	lg, _ := math.Lgamma(float64(n) + 1)
+	return lg / math.Ln2
+}
+
+func (e *evaluator) fact(col int, a value) (value, error) {
+	n, err := integer(col, a, "factorial argument")
+	if err != nil {
+		return value{}, err
+	}
+	if n < 0 {
+		return value{}, errorf(col, "factorial of a negative number")
+	}
+	if log2Fact(n) > maxBits {
+		return value{}, errorf(col, "result too large")
+	}
+	return value{r: new(big.Rat).SetInt(new(big.Int).MulRange(1, n))}, nil
+}
+
+func (e *evaluator) binom(col int, a, b value) (value, error) {
+	n, err := integer(col, a, "binom argument")
+	if err != nil {
+		return value{}, err
+	}
+	k, err := integer(col, b, "binom argument")
+	if err != nil {
+		return value{}, err
+	}
+	if n < 0 || k < 0 {
+		return value{}, errorf(col, "binom of a negative number")
+	}
+	if k > n {
+		return value{r: new(big.Rat)}, nil
+	}
+	if log2Fact(n)-log2Fact(k)-log2Fact(n-k) > maxBits {
+		return value{}, errorf(col, "result too large")
+	}
+	return value{r: new(big.Rat).SetInt(new(big.Int).Binomial(n, k))}, nil
+}
+
+// fibonacci asks the fib service, whose results are shared and so copied.
+func (e *evaluator) fibonacci(col int, a value) (value, error) {
+	n, err := integer(col, a, "fib argument")
+	if err != nil {
+		return value{}, err
+	}
+	if n < 0 {
+		return value{}, errorf(col, "fib of a negative number")
+	}
+	if float64(n)*math.Log2(math.Phi) > maxBits {
+		return value{}, errorf(col, "result too large")
+	}
+	v, err := e.fib.Fib(e.ctx, uint64(n))
+	if err != nil {
+		return value{}, err
+	}
+	return value{r: new(big.Rat).SetInt(v)}, nil
+}
@@ -0,0 +1,71 @@
+package calc
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func evaluate(t *testing.T, expr string) (value, error) {
+	t.Helper()
+	tree, err := parse(expr)
+	if err != nil {
+		t.Fatalf("parse(%q): %v", expr, err)
+	}
+	e := &evaluator{ctx: context.Background(), prec: 100 + 16}
+	return e.eval(tree)
+}
+
+func TestFloatModAndPow(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"7.5 % 2", "3/2"},
+		{"-7 % 2", "1"},
+		{"sqrt(2) % 1", "0.414213562"},
+		{"-sqrt(2) % 1", "0.585786438"},
+		{"sqrt(2) % -1", "-0.585786438"},
+		{"sqrt(2)^2", "2"},
+		{"sqrt(2)^-2", "0.5"},
+		{"sqrt(2)^60 % 7", "1"},
+		{"sqrt(2)^2147483648 % sqrt(2)^2147483648", "0"},
+	}
+	for _, tt := range tests {
+		v, err := evaluate(t, tt.expr)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.expr, err)
+			continue
+		}
+		var got string
+		if v.exact() {
+			got = v.r.RatString()
+		} else {
+			got = v.f.Text('g', 9)
+		}
+		if got != tt.want {
+			t.Errorf("%s = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestFloatExponentRange(t *testing.T) {
+	tests := []string{
+		"sqrt(2)^4294967296",
+		"(sqrt(2)^2147483648) % (sqrt(2)^-2147483648)",
+		"(sqrt(2)^2147483646) % 3",
+		"(sqrt(2)^2147483646) % (sqrt(2)^-2147483646)",
+		"sqrt(2) % (sqrt(2)^-2000000)",
+	}
+	for _, expr := range tests {
+		_, err := evaluate(t, expr)
+		var cerr *Error
+		if !errors.As(err, &cerr) {
+			t.Errorf("%s: got %v, want *Error", expr, err)
+			continue
+		}
+		if cerr.Msg != "result too large" {
+			t.Errorf("%s: got %q, want %q", expr, cerr.Msg, "result too large")
+		}
+	}
+}
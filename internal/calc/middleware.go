@@ -0,0 +1,71 @@
+package calc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"go.uber.org/zap"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger zap.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+func NewInstrumentingMiddleware(
+	counter metrics.Counter,
+	latency metrics.Histogram,
+	s Service,
+) Service {
+	return &instrumentingMiddleware{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger zap.Logger
+}
+
+type instrumentingMiddleware struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+func (mw loggingMiddleware) Evaluate(ctx context.Context, expr string, precision int) (res Result, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Evaluate"),
+			zap.String("input", expr),
+			zap.Int("precision", precision),
+			zap.Bool("exact", res.Exact),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	res, err = mw.next.Evaluate(ctx, expr, precision)
+	return
+}
+
+func (mw instrumentingMiddleware) Evaluate(ctx context.Context, expr string, precision int) (res Result, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "evaluate", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	res, err = mw.next.Evaluate(ctx, expr, precision)
+	return
+}
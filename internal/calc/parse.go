@@ -0,0 +1,293 @@
+package calc
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Error is a syntax or evaluation error at a 1-based column of the
+// expression, counted in runes.
+type Error struct {
+	Column int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at column %d", e.Msg, e.Column)
+}
+
+func errorf(col int, format string, args ...interface{}) *Error {
+	return &Error{col, fmt.Sprintf(format, args...)}
+}
+
+const (
+	tokNumber = iota
+	tokIdent
+	tokOp
+	tokEOF
+)
+
+type token struct {
+	kind int
+	text string
+	col  int
+}
+
+// lex splits an expression into numbers, identifiers and single character
+// operators.
+func lex(expr string) ([]token, error) {
+	rs := []rune(expr)
+	var toks []token
+	for i := 0; i < len(rs); {
+		r, col := rs[i], i+1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			if j < len(rs) && (rs[j] == 'e' || rs[j] == 'E') {
+				k := j + 1
+				if k < len(rs) && (rs[k] == '+' || rs[k] == '-') {
+					k++
+				}
+				if k < len(rs) && unicode.IsDigit(rs[k]) {
+					for k < len(rs) && unicode.IsDigit(rs[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+			toks = append(toks, token{tokNumber, string(rs[i:j]), col})
+			i = j
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j])) {
+				j++
+			}
+			toks = append(toks, token{tokIdent, strings.ToLower(string(rs[i:j])), col})
+			i = j
+		case strings.ContainsRune("+-*/%^!(),", r):
+			toks = append(toks, token{tokOp, string(r), col})
+			i++
+		default:
+			return nil, errorf(col, "unexpected character %q", r)
+		}
+	}
+	return append(toks, token{tokEOF, "", len(rs) + 1}), nil
+}
+
+// node is an expression tree. Literals hold num; operators and functions
+// hold their operands in args.
+type node struct {
+	op   string
+	col  int
+	num  *big.Rat
+	args []*node
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(op string) (token, bool) {
+	if t := p.peek(); t.kind == tokOp && t.text == op {
+		return p.next(), true
+	}
+	return token{}, false
+}
+
+// parse builds the tree of an expression in the grammar
+//
+//	expr    = term {("+" | "-") term}
+//	term    = unary {("*" | "/" | "%") unary}
+//	unary   = ("-" | "+") unary | power
+//	power   = postfix ["^" unary]
+//	postfix = primary {"!"}
+//	primary = number | ident "(" [expr {"," expr}] ")" | "(" expr ")"
+//
+// so that ^ binds tighter than unary minus and groups to the right.
+func parse(expr string) (*node, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	n, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorf(t.col, "unexpected %q", t.text)
+	}
+	return n, nil
+}
+
+func (p *parser) binary(operand func() (*node, error), ops ...string) (*node, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var t token
+		ok := false
+		for _, op := range ops {
+			if t, ok = p.accept(op); ok {
+				break
+			}
+		}
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &node{op: t.text, col: t.col, args: []*node{left, right}}
+	}
+}
+
+func (p *parser) expr() (*node, error) {
+	return p.binary(p.term, "+", "-")
+}
+
+func (p *parser) term() (*node, error) {
+	return p.binary(p.unary, "*", "/", "%")
+}
+
+func (p *parser) unary() (*node, error) {
+	if t, ok := p.accept("-"); ok {
+		n, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &node{op: "neg", col: t.col, args: []*node{n}}, nil
+	}
+	if _, ok := p.accept("+"); ok {
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (*node, error) {
+	base, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := p.accept("^"); ok {
+		exp, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return &node{op: "^", col: t.col, args: []*node{base, exp}}, nil
+	}
+	return base, nil
+}
+
+func (p *parser) postfix() (*node, error) {
+	n, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.accept("!")
+		if !ok {
+			return n, nil
+		}
+		n = &node{op: "fact", col: t.col, args: []*node{n}}
+	}
+}
+
+func (p *parser) primary() (*node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return number(t)
+	case tokIdent:
+		arity, ok := functions[t.text]
+		if !ok {
+			return nil, errorf(t.col, "unknown function %q", t.text)
+		}
+		if _, ok := p.accept("("); !ok {
+			return nil, errorf(p.peek().col, "expected ( after %s", t.text)
+		}
+		n := &node{op: t.text, col: t.col}
+		if _, ok := p.accept(")"); !ok {
+			for {
+				arg, err := p.expr()
+				if err != nil {
+					return nil, err
+				}
+				n.args = append(n.args, arg)
+				if _, ok := p.accept(","); !ok {
+					break
+				}
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, errorf(p.peek().col, "expected , or )")
+			}
+		}
+		if len(n.args) != arity {
+			return nil, errorf(t.col, "%s takes %d arguments", t.text, arity)
+		}
+		return n, nil
+	case tokOp:
+		if t.text == "(" {
+			n, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, errorf(p.peek().col, "expected )")
+			}
+			return n, nil
+		}
+		return nil, errorf(t.col, "unexpected %q", t.text)
+	default:
+		return nil, errorf(t.col, "unexpected end of expression")
+	}
+}
+
+// number parses a decimal literal such as 12, 1.5 or 2.5e-3 exactly,
+// refusing exponents that would make it too large to hold.
+func number(t token) (*node, error) {
+	mant, exp := t.text, ""
+	if i := strings.IndexAny(t.text, "eE"); i >= 0 {
+		mant, exp = t.text[:i], t.text[i+1:]
+	}
+	if strings.Count(mant, ".") > 1 || mant == "." {
+		return nil, errorf(t.col, "malformed number %q", t.text)
+	}
+	if exp != "" {
+		e, err := strconv.Atoi(exp)
+		if err != nil || e > maxDigits || e < -maxDigits {
+			return nil, errorf(t.col, "exponent out of range in %q", t.text)
+		}
+	}
+	if len(mant) > maxDigits {
+		return nil, errorf(t.col, "number too long")
+	}
+	r, ok := new(big.Rat).SetString(t.text)
+	if !ok {
+		return nil, errorf(t.col, "malformed number %q", t.text)
+	}
+	return &node{op: "num", col: t.col, num: r}, nil
+}
@@ -0,0 +1,92 @@
+package calc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/daaser/server/internal/fib"
+)
+
+type Service interface {
+	Evaluate(ctx context.Context, expr string, precision int) (Result, error)
+}
+
+// Result is the value of an expression. Exact results are integers or
+// fractions in lowest terms, with Decimal their expansion to the requested
+// number of significant digits; inexact ones only have Value.
+type Result struct {
+	Value   string `json:"value"`
+	Decimal string `json:"decimal,omitempty"`
+	Exact   bool   `json:"exact"`
+}
+
+const (
+	maxExprLength = 10000
+	maxPrecision  = 1000
+)
+
+var (
+	ErrEmpty     = errors.New("Empty expression")
+	ErrTooLong   = errors.New("Expression too long")
+	ErrPrecision = errors.New("Precision out of range")
+	ErrTimeout   = errors.New("Evaluation took too long")
+)
+
+// Config holds the limits of a calculator service.
+type Config struct {
+	// Timeout bounds the evaluation of an expression.
+	Timeout time.Duration
+}
+
+type service struct {
+	timeout time.Duration
+	fib     fib.Service
+}
+
+// Evaluate parses and evaluates expr, keeping values exact where it can and
+// otherwise working with floats of precision significant decimal digits.
+func (svc *service) Evaluate(ctx context.Context, expr string, precision int) (Result, error) {
+	if expr == "" {
+		return Result{}, ErrEmpty
+	}
+	if len(expr) > maxExprLength {
+		return Result{}, ErrTooLong
+	}
+	if precision < 1 || precision > maxPrecision {
+		return Result{}, ErrPrecision
+	}
+	tree, err := parse(expr)
+	if err != nil {
+		return Result{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, svc.timeout)
+	defer cancel()
+	// a few guard bits keep the last requested digit right
+	prec := uint(math.Ceil(float64(precision)*math.Log2(10))) + 16
+	e := &evaluator{ctx: ctx, prec: prec, fib: svc.fib}
+	v, err := e.eval(tree)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Result{}, ErrTimeout
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	if !v.exact() {
+		return Result{Value: v.f.Text('g', precision)}, nil
+	}
+	res := Result{Value: v.r.RatString(), Exact: true}
+	if !v.r.IsInt() {
+		res.Decimal = new(big.Float).SetPrec(prec).SetRat(v.r).Text('g', precision)
+	}
+	return res, nil
+}
+
+// NewService returns a calculator that computes Fibonacci numbers with fs.
+func NewService(cfg Config, fs fib.Service) Service {
+	return &service{timeout: cfg.Timeout, fib: fs}
+}
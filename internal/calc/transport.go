@@ -0,0 +1,63 @@
+package calc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/daaser/server/internal/fib"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+var BadRequest = errors.New("Bad expression in request")
+
+func MakeHandler(cs Service) http.Handler {
+	calcHandler := kithttp.NewServer(
+		makeCalcEndpoint(cs),
+		decodeCalcRequest,
+		encodeResponse,
+		kithttp.ServerErrorEncoder(encodeError),
+	)
+
+	r := mux.NewRouter()
+
+	r.Path("/calc").Handler(calcHandler).Methods("POST")
+
+	return r
+}
+
+func decodeCalcRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request calcRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadRequest
+	}
+	return request, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeError writes err as JSON with a status code matching its cause.
+// Syntax and evaluation errors carry the column they occurred at.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	body := map[string]interface{}{"err": err.Error()}
+	var cerr *Error
+	switch {
+	case errors.As(err, &cerr):
+		body["err"] = cerr.Msg
+		body["column"] = cerr.Column
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, ErrTimeout), errors.Is(err, fib.NumberTooLarge):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Is(err, BadRequest), errors.Is(err, ErrEmpty),
+		errors.Is(err, ErrTooLong), errors.Is(err, ErrPrecision):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,41 @@
+package digest
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type digestRequest struct {
+	Data       []byte   `json:"data"`
+	Algorithms []string `json:"algorithms"`
+	Key        string   `json:"key,omitempty"`
+	Encoding   string   `json:"encoding,omitempty"`
+	// Src, when set, is read in place of Data.
+	Src io.Reader `json:"-"`
+}
+
+type digestResponse struct {
+	Digests map[string]string `json:"digests,omitempty"`
+	Err     string            `json:"err,omitempty"`
+}
+
+func makeDigestEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(digestRequest)
+		var (
+			ds  map[string]string
+			err error
+		)
+		if req.Src != nil {
+			ds, err = svc.DigestStream(req.Src, req.Algorithms, []byte(req.Key), req.Encoding)
+		} else {
+			ds, err = svc.Digest(req.Data, req.Algorithms, []byte(req.Key), req.Encoding)
+		}
+		if err != nil {
+			return digestResponse{nil, err.Error()}, nil
+		}
+		return digestResponse{ds, ""}, nil
+	}
+}
@@ -0,0 +1,98 @@
+package digest
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"go.uber.org/zap"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger zap.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+func NewInstrumentingMiddleware(
+	counter metrics.Counter,
+	latency metrics.Histogram,
+	s Service,
+) Service {
+	return &instrumentingMiddleware{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger zap.Logger
+}
+
+type instrumentingMiddleware struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+func (mw loggingMiddleware) Digest(data []byte, algorithms []string, key []byte, encoding string) (output map[string]string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Digest"),
+			zap.Int("size", len(data)),
+			zap.Strings("algorithms", algorithms),
+			zap.String("encoding", encoding),
+			zap.Any("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Digest(data, algorithms, key, encoding)
+	return
+}
+
+func (mw loggingMiddleware) DigestStream(src io.Reader, algorithms []string, key []byte, encoding string) (output map[string]string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "DigestStream"),
+			zap.Strings("algorithms", algorithms),
+			zap.String("encoding", encoding),
+			zap.Any("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.DigestStream(src, algorithms, key, encoding)
+	return
+}
+
+func (mw instrumentingMiddleware) Digest(data []byte, algorithms []string, key []byte, encoding string) (output map[string]string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "digest", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Digest(data, algorithms, key, encoding)
+	return
+}
+
+func (mw instrumentingMiddleware) DigestStream(src io.Reader, algorithms []string, key []byte, encoding string) (output map[string]string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "digeststream", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.DigestStream(src, algorithms, key, encoding)
+	return
+}
@@ -0,0 +1,108 @@
+package digest
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/fnv"
+	"io"
+	"strings"
+)
+
+type Service interface {
+	Digest(data []byte, algorithms []string, key []byte, encoding string) (map[string]string, error)
+	DigestStream(src io.Reader, algorithms []string, key []byte, encoding string) (map[string]string, error)
+}
+
+var (
+	ErrAlgorithm = errors.New("Unknown hash algorithm")
+	ErrKey       = errors.New("HMAC requires a key")
+	ErrEncoding  = errors.New("Unknown output encoding")
+)
+
+var hashes = map[string]func() hash.Hash{
+	"crc32":   func() hash.Hash { return crc32.NewIEEE() },
+	"adler32": func() hash.Hash { return adler32.New() },
+	"fnv1a32": func() hash.Hash { return fnv.New32a() },
+	"fnv1a64": func() hash.Hash { return fnv.New64a() },
+	"md5":     md5.New,
+	"sha1":    sha1.New,
+	"sha256":  sha256.New,
+	"sha512":  sha512.New,
+}
+
+type service struct{}
+
+// Digest hashes data with each of the named algorithms. An "hmac-" prefix,
+// e.g. "hmac-sha256", keys the algorithm with key. With no algorithms it
+// defaults to SHA-256, and with no encoding to hex.
+func (s service) Digest(data []byte, algorithms []string, key []byte, encoding string) (map[string]string, error) {
+	return s.DigestStream(bytes.NewReader(data), algorithms, key, encoding)
+}
+
+// DigestStream is Digest for data read from src, which is fed to every
+// hash in a single pass without being held in memory.
+func (service) DigestStream(src io.Reader, algorithms []string, key []byte, encoding string) (map[string]string, error) {
+	var encode func([]byte) string
+	switch strings.ToLower(encoding) {
+	case "", "hex":
+		encode = hex.EncodeToString
+	case "base64":
+		encode = base64.StdEncoding.EncodeToString
+	default:
+		return nil, ErrEncoding
+	}
+	if len(algorithms) == 0 {
+		algorithms = []string{"sha256"}
+	}
+
+	hs := make(map[string]hash.Hash, len(algorithms))
+	ws := make([]io.Writer, 0, len(algorithms))
+	for _, alg := range algorithms {
+		name := strings.ToLower(alg)
+		if _, ok := hs[name]; ok {
+			continue
+		}
+		var h hash.Hash
+		if inner := strings.TrimPrefix(name, "hmac-"); inner != name {
+			newHash, ok := hashes[inner]
+			if !ok {
+				return nil, ErrAlgorithm
+			}
+			if len(key) == 0 {
+				return nil, ErrKey
+			}
+			h = hmac.New(newHash, key)
+		} else {
+			newHash, ok := hashes[name]
+			if !ok {
+				return nil, ErrAlgorithm
+			}
+			h = newHash()
+		}
+		hs[name] = h
+		ws = append(ws, h)
+	}
+	if _, err := io.Copy(io.MultiWriter(ws...), src); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(hs))
+	for name, h := range hs {
+		out[name] = encode(h.Sum(nil))
+	}
+	return out, nil
+}
+
+func NewService() Service {
+	return &service{}
+}
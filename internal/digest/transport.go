@@ -0,0 +1,106 @@
+package digest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// maxStreamBytes bounds an application/octet-stream body. It is hashed
+	// as it is read, so this limits the time spent rather than memory.
+	maxStreamBytes = 1 << 30
+	// streamTimeout is how long a raw body may go without a chunk being
+	// read. It replaces the server's whole-request timeouts, which would
+	// cut off large bodies.
+	streamTimeout = 10 * time.Second
+)
+
+func MakeHandler(ds Service) http.Handler {
+	digestHandler := kithttp.NewServer(
+		makeDigestEndpoint(ds),
+		decodeDigestRequest,
+		encodeResponse,
+	)
+
+	r := mux.NewRouter()
+
+	r.Path("/digest").
+		MatcherFunc(func(r *http.Request, _ *mux.RouteMatch) bool {
+			return isOctetStream(r)
+		}).
+		Handler(streamBody(digestHandler, maxStreamBytes)).
+		Methods("POST")
+	r.Path("/digest").Handler(digestHandler).Methods("POST")
+
+	return r
+}
+
+func isOctetStream(r *http.Request) bool {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mt == "application/octet-stream"
+}
+
+// streamBody fails reads past n bytes of the request body, and extends the
+// deadlines before each read so a slow upload, and the response that
+// follows it, are not cut off.
+func streamBody(h http.Handler, n int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = &streamReader{
+			http.MaxBytesReader(w, r.Body, n),
+			http.NewResponseController(w),
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+type streamReader struct {
+	io.ReadCloser
+	rc *http.ResponseController
+}
+
+func (sr *streamReader) Read(p []byte) (int, error) {
+	deadline := time.Now().Add(streamTimeout)
+	sr.rc.SetReadDeadline(deadline)
+	sr.rc.SetWriteDeadline(deadline)
+	return sr.ReadCloser.Read(p)
+}
+
+// decodeDigestRequest accepts either a JSON body with "text" or base64
+// "data", or a raw application/octet-stream body with the options given as
+// query parameters, e.g. ?alg=sha256&alg=md5&encoding=base64. The raw body
+// is hashed as it streams in. Its HMAC key is taken from the X-HMAC-Key
+// header, keeping it out of URLs and access logs.
+func decodeDigestRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if isOctetStream(r) {
+		q := r.URL.Query()
+		return digestRequest{
+			Src:        r.Body,
+			Algorithms: q["alg"],
+			Key:        r.Header.Get("X-HMAC-Key"),
+			Encoding:   q.Get("encoding"),
+		}, nil
+	}
+
+	var request struct {
+		digestRequest
+		Text *string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	if request.Text != nil {
+		request.Data = []byte(*request.Text)
+	}
+	return request.digestRequest, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
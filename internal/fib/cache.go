@@ -0,0 +1,80 @@
+package fib
+
+import (
+	"container/list"
+	"math/big"
+	"sync"
+
+	"github.com/go-kit/kit/metrics"
+)
+
+// cache is an LRU cache of Fibonacci numbers bounded by the total size of
+// the cached values rather than their count, since F(n) takes about 0.7n
+// bits. It is safe for concurrent use.
+type cache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	ll       *list.List
+	items    map[uint64]*list.Element
+
+	hits   metrics.Counter
+	misses metrics.Counter
+}
+
+type cacheEntry struct {
+	n uint64
+	v *big.Int
+}
+
+func newCache(maxBytes int, hits, misses metrics.Counter) *cache {
+	return &cache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[uint64]*list.Element),
+		hits:     hits,
+		misses:   misses,
+	}
+}
+
+// entrySize approximates the memory held by a cached value.
+func entrySize(v *big.Int) int {
+	return (v.BitLen() + 7) / 8
+}
+
+// get returns the cached F(n). The value is shared and must not be
+// modified.
+func (c *cache) get(n uint64) (*big.Int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[n]; ok {
+		c.ll.MoveToFront(e)
+		c.hits.Add(1)
+		return e.Value.(*cacheEntry).v, true
+	}
+	c.misses.Add(1)
+	return nil, false
+}
+
+// add caches F(n), evicting the least recently used values to make room.
+// Values larger than the whole cache are not kept.
+func (c *cache) add(n uint64, v *big.Int) {
+	size := entrySize(v)
+	if size > c.maxBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.items[n]; ok {
+		return
+	}
+	c.items[n] = c.ll.PushFront(&cacheEntry{n, v})
+	c.size += size
+	for c.size > c.maxBytes {
+		e := c.ll.Back()
+		ent := e.Value.(*cacheEntry)
+		c.ll.Remove(e)
+		delete(c.items, ent.n)
+		c.size -= entrySize(ent.v)
+	}
+}
@@ -0,0 +1,50 @@
+package fib
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/go-kit/kit/metrics/discard"
+)
+
+func TestCacheEviction(t *testing.T) {
+	// each value takes 8 bytes, so the cache holds three
+	c := newCache(24, discard.NewCounter(), discard.NewCounter())
+	v := func(n uint64) *big.Int {
+		return new(big.Int).Lsh(big.NewInt(int64(n)+1), 56)
+	}
+	for n := uint64(0); n < 3; n++ {
+		c.add(n, v(n))
+	}
+	if c.size != 24 {
+		t.Fatalf("size = %d, want 24", c.size)
+	}
+	// touch 0 so that 1 is the least recently used
+	if _, ok := c.get(0); !ok {
+		t.Fatal("get(0) missed")
+	}
+	c.add(3, v(3))
+
+	if c.size > c.maxBytes {
+		t.Errorf("size = %d, over the limit of %d", c.size, c.maxBytes)
+	}
+	for n, want := range map[uint64]bool{0: true, 1: false, 2: true, 3: true} {
+		got, ok := c.get(n)
+		if ok != want {
+			t.Errorf("get(%d) hit = %v, want %v", n, ok, want)
+		} else if ok && got.Cmp(v(n)) != 0 {
+			t.Errorf("get(%d) = %s, want %s", n, got, v(n))
+		}
+	}
+}
+
+func TestCacheTooLarge(t *testing.T) {
+	c := newCache(8, discard.NewCounter(), discard.NewCounter())
+	c.add(1, new(big.Int).Lsh(big.NewInt(1), 64))
+	if _, ok := c.get(1); ok {
+		t.Error("value larger than the cache was kept")
+	}
+	if c.size != 0 {
+		t.Errorf("size = %d, want 0", c.size)
+	}
+}
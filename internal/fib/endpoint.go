@@ -2,23 +2,132 @@ package fib
 
 import (
 	"context"
+	"math/big"
 
 	"github.com/go-kit/kit/endpoint"
 )
 
 type fibRequest struct {
-	N uint64
+	N      uint64
+	Format outputFormat
 }
 
 type fibResponse struct {
-	Fib string `json:"fib,omitempty"`
+	Fib    string `json:"fib,omitempty"`
+	Digits int    `json:"digits,omitempty"`
+}
+
+type fibModRequest struct {
+	N uint64
+	M uint64
+}
+
+type fibModResponse struct {
+	Fib uint64 `json:"fib"`
+}
+
+type pisanoRequest struct {
+	M uint64
+}
+
+type pisanoResponse struct {
+	Period uint64 `json:"period"`
+}
+
+type sequenceRequest struct {
+	Recurrence Recurrence
+	N          uint64
+	Format     outputFormat
+}
+
+// numberRequest carries a decimal string so that numbers beyond the
+// precision of JSON numbers survive.
+type numberRequest struct {
+	X string `json:"x"`
+}
+
+type indexResponse struct {
+	Fibonacci bool    `json:"fibonacci"`
+	Index     *uint64 `json:"index,omitempty"`
+}
+
+type zeckendorfResponse struct {
+	Indices []uint64 `json:"indices"`
 }
 
 func makeFibEndpoint(svc Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(fibRequest)
-		fib := svc.Fib(req.N)
-		// return fibResponse{fib.String()}, nil
-		return fib, nil
+		fib, err := svc.Fib(ctx, req.N)
+		if err != nil {
+			return nil, err
+		}
+		return formatFib(fib, req.Format), nil
+	}
+}
+
+func makeFibModEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(fibModRequest)
+		v, err := svc.FibMod(req.N, req.M)
+		if err != nil {
+			return nil, err
+		}
+		return fibModResponse{v}, nil
+	}
+}
+
+func makePisanoEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(pisanoRequest)
+		period, err := svc.Pisano(req.M)
+		if err != nil {
+			return nil, err
+		}
+		return pisanoResponse{period}, nil
+	}
+}
+
+func makeSequenceEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sequenceRequest)
+		v, err := svc.Sequence(ctx, req.Recurrence, req.N)
+		if err != nil {
+			return nil, err
+		}
+		return formatFib(v, req.Format), nil
+	}
+}
+
+func makeIndexEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(numberRequest)
+		x, ok := new(big.Int).SetString(req.X, 10)
+		if !ok {
+			return nil, BadNumber
+		}
+		n, ok, err := svc.Index(ctx, x)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return indexResponse{false, nil}, nil
+		}
+		return indexResponse{true, &n}, nil
+	}
+}
+
+func makeZeckendorfEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(numberRequest)
+		x, ok := new(big.Int).SetString(req.X, 10)
+		if !ok {
+			return nil, BadNumber
+		}
+		indices, err := svc.Zeckendorf(ctx, x)
+		if err != nil {
+			return nil, err
+		}
+		return zeckendorfResponse{indices}, nil
 	}
 }
@@ -0,0 +1,134 @@
+package fib
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var BadFormat = errors.New("bad format in request")
+
+const (
+	formatDecimal    = "decimal"
+	formatHex        = "hex"
+	formatBase       = "base"
+	formatDigits     = "digits"
+	formatLeading    = "leading"
+	formatTrailing   = "trailing"
+	formatScientific = "scientific"
+)
+
+// maxFormatDigits bounds k for the leading, trailing and scientific formats.
+const maxFormatDigits = 1000
+
+// outputFormat describes how a Fibonacci number is written. Base applies to
+// the base format; K is the number of digits kept by the leading, trailing
+// and scientific formats.
+type outputFormat struct {
+	Name string
+	Base int
+	K    int
+}
+
+// parseFormat reads the format, base and k query parameters.
+func parseFormat(format, base, k string) (outputFormat, error) {
+	f := outputFormat{Name: format, Base: 10, K: 10}
+	switch format {
+	case "":
+		f.Name = formatDecimal
+	case formatDecimal, formatDigits:
+	case formatHex:
+		f.Base = 16
+	case formatBase:
+		b, err := strconv.Atoi(base)
+		if err != nil || b < 2 || b > big.MaxBase {
+			return f, BadFormat
+		}
+		f.Base = b
+	case formatLeading, formatTrailing, formatScientific:
+		if k != "" {
+			n, err := strconv.Atoi(k)
+			if err != nil || n < 1 || n > maxFormatDigits {
+				return f, BadFormat
+			}
+			f.K = n
+		}
+	default:
+		return f, BadFormat
+	}
+	return f, nil
+}
+
+// formatFib writes v in format f. Only the decimal, hex and base formats
+// convert the whole number; the rest need a handful of big operations.
+func formatFib(v *big.Int, f outputFormat) fibResponse {
+	switch f.Name {
+	case formatDigits:
+		return fibResponse{Digits: decimalDigits(v)}
+	case formatLeading:
+		d := decimalDigits(v)
+		return fibResponse{Fib: leadingDigits(v, d, f.K).String(), Digits: d}
+	case formatTrailing:
+		m := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(f.K)), nil)
+		s := new(big.Int).Mod(v, m).String()
+		if v.Cmp(m) >= 0 {
+			// keep the zeros among the last k digits
+			s = strings.Repeat("0", f.K-len(s)) + s
+		}
+		return fibResponse{Fib: s}
+	case formatScientific:
+		d := decimalDigits(v)
+		return fibResponse{Fib: scientific(v, d, f.K), Digits: d}
+	default:
+		return fibResponse{Fib: v.Text(f.Base)}
+	}
+}
+
+// decimalDigits counts the decimal digits of v. Its bit length b puts the
+// count between those of 2^(b-1) and 2^b, which differ by at most one, so
+// the estimate is checked against a single power of ten.
+func decimalDigits(v *big.Int) int {
+	if v.Sign() == 0 {
+		return 1
+	}
+	d := int(float64(v.BitLen()-1)*math.Log10(2)) + 1
+	pow := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d-1)), nil)
+	switch {
+	case v.Cmp(pow) < 0:
+		d--
+	case v.Cmp(pow.Mul(pow, big.NewInt(10))) >= 0:
+		d++
+	}
+	return d
+}
+
+// leadingDigits returns the first k of the d decimal digits of v.
+func leadingDigits(v *big.Int, d, k int) *big.Int {
+	if k >= d {
+		return new(big.Int).Set(v)
+	}
+	div := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(d-k)), nil)
+	return new(big.Int).Quo(v, div)
+}
+
+// scientific writes v, which has d decimal digits, rounded to k significant
+// digits as in 1.2345e+67.
+func scientific(v *big.Int, d, k int) string {
+	exp := d - 1
+	m := leadingDigits(v, d, k+1)
+	if d > k {
+		// round half up on the extra digit
+		m.Add(m, big.NewInt(5)).Quo(m, big.NewInt(10))
+		if len(m.String()) > k {
+			m.Quo(m, big.NewInt(10))
+			exp++
+		}
+	}
+	s := m.String()
+	if len(s) > 1 {
+		s = s[:1] + "." + s[1:]
+	}
+	return s + "e+" + strconv.Itoa(exp)
+}
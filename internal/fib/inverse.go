@@ -0,0 +1,84 @@
+package fib
+
+import (
+	"context"
+	"math"
+	"math/big"
+)
+
+// maxZeckendorfBits bounds the numbers decomposed, as the walk down the
+// sequence costs a subtraction per index.
+const maxZeckendorfBits = 1 << 15
+
+var log2Phi = math.Log2(math.Phi)
+
+// log2 returns the base two logarithm of x > 0 to float64 precision.
+func log2(x *big.Int) float64 {
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(x).MantExp(mant)
+	m, _ := mant.Float64()
+	return float64(exp) + math.Log2(m)
+}
+
+// estimateIndex returns the index n for which F(n) is closest to x > 0,
+// from F(n) ≈ φⁿ/√5.
+func estimateIndex(x *big.Int) uint64 {
+	return uint64(math.Round((log2(x) + math.Log2(math.Sqrt(5))) / log2Phi))
+}
+
+// fibIndex reports the index of x in the Fibonacci sequence, taking 1 to be
+// F(1) rather than F(2).
+func fibIndex(ctx context.Context, x *big.Int) (uint64, bool, error) {
+	switch x.Sign() {
+	case -1:
+		return 0, false, nil
+	case 0:
+		return 0, true, nil
+	}
+	if x.Cmp(big.NewInt(1)) == 0 {
+		return 1, true, nil
+	}
+	n := estimateIndex(x)
+	v, _, err := fibPair(ctx, n)
+	if err != nil {
+		return 0, false, err
+	}
+	return n, v.Cmp(x) == 0, nil
+}
+
+// zeckendorf returns the indices, largest first and all at least 2, of the
+// unique set of non-consecutive Fibonacci numbers summing to x ≥ 0. It
+// walks down the sequence from the largest F(k) ≤ x, subtracting each term
+// that fits; what remains is then always less than the next term.
+func zeckendorf(ctx context.Context, x *big.Int) ([]uint64, error) {
+	indices := []uint64{}
+	if x.Sign() == 0 {
+		return indices, nil
+	}
+	k := estimateIndex(x) + 1
+	a, b, err := fibPair(ctx, k)
+	if err != nil {
+		return nil, err
+	}
+	// a, b = F(k), F(k+1) with F(k) ≤ x
+	for a.Cmp(x) > 0 {
+		a, b = b.Sub(b, a), a
+		k--
+	}
+
+	rest := new(big.Int).Set(x)
+	for rest.Sign() > 0 {
+		if k%64 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if a.Cmp(rest) <= 0 {
+			rest.Sub(rest, a)
+			indices = append(indices, k)
+		}
+		a, b = b.Sub(b, a), a
+		k--
+	}
+	return indices, nil
+}
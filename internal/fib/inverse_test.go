@@ -0,0 +1,53 @@
+package fib
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestZeckendorf(t *testing.T) {
+	fs := iterative(200)
+	check := func(x *big.Int) {
+		t.Helper()
+		indices, err := zeckendorf(context.Background(), x)
+		if err != nil {
+			t.Fatalf("zeckendorf(%s): %v", x, err)
+		}
+		sum := new(big.Int)
+		for i, k := range indices {
+			if k < 2 {
+				t.Errorf("zeckendorf(%s) uses F(%d)", x, k)
+			}
+			if i > 0 && indices[i-1] < k+2 {
+				t.Errorf("zeckendorf(%s) = %v has consecutive or unordered terms", x, indices)
+			}
+			sum.Add(sum, fs[k])
+		}
+		if sum.Cmp(x) != 0 {
+			t.Errorf("zeckendorf(%s) = %v sums to %s", x, indices, sum)
+		}
+	}
+	for x := int64(0); x <= 1000; x++ {
+		check(big.NewInt(x))
+	}
+	// one less than a Fibonacci number needs every other term below it
+	check(new(big.Int).Sub(fs[150], big.NewInt(1)))
+	check(fs[199])
+}
+
+// F(1) = F(2) = 1, so indices below 3 are ambiguous.
+func TestFibIndex(t *testing.T) {
+	fs := iterative(300)
+	for n := 3; n <= 300; n++ {
+		k, ok, err := fibIndex(context.Background(), fs[n])
+		if err != nil || !ok || k != uint64(n) {
+			t.Errorf("fibIndex(F(%d)) = %d, %v, %v", n, k, ok, err)
+		}
+		if n > 4 {
+			if _, ok, _ := fibIndex(context.Background(), new(big.Int).Add(fs[n], big.NewInt(1))); ok {
+				t.Errorf("fibIndex(F(%d)+1) reported a Fibonacci number", n)
+			}
+		}
+	}
+}
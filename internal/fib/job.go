@@ -0,0 +1,72 @@
+package fib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/daaser/server/internal/job"
+)
+
+type progressKey struct{}
+
+// withProgress makes fibPair report its progress on ctx to fn.
+func withProgress(ctx context.Context, fn func(float64)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// reportProgress estimates the share of the work done after step of steps
+// doublings. Each step multiplies numbers twice the size of the last, so
+// costs about three times as much with Karatsuba multiplication.
+func reportProgress(ctx context.Context, step, steps int) {
+	if fn, ok := ctx.Value(progressKey{}).(func(float64)); ok {
+		p := 1.0
+		for i := step; i < steps; i++ {
+			p /= 3
+		}
+		fn(p)
+	}
+}
+
+type jobParams struct {
+	N      uint64 `json:"n"`
+	Format string `json:"format"`
+	Base   int    `json:"base"`
+	K      int    `json:"k"`
+}
+
+// NewJob returns a job kind computing F(n) with svc.FibJob for n up to
+// maxN, which should match the service's MaxJobN so that too large an n is
+// rejected at submission. It takes n and the format options of /fib/{n},
+// and its result is the same response.
+func NewJob(svc Service, maxN uint64) job.Kind {
+	return func(params json.RawMessage) (job.Task, error) {
+		var p jobParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, BadNumber
+		}
+		if p.N > maxN {
+			return nil, fmt.Errorf("%w: maximum is %d", NumberTooLarge, maxN)
+		}
+		format, err := parseFormat(p.Format, itoa(p.Base), itoa(p.K))
+		if err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context, progress func(float64)) (interface{}, error) {
+			v, err := svc.FibJob(withProgress(ctx, progress), p.N)
+			if err != nil {
+				return nil, err
+			}
+			return formatFib(v, format), nil
+		}, nil
+	}
+}
+
+// itoa leaves unset options empty.
+func itoa(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
@@ -1,6 +1,8 @@
 package fib
 
 import (
+	"context"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -43,25 +45,204 @@ type instrumentingMiddleware struct {
 	next           Service
 }
 
-func (mw loggingMiddleware) Fib(n uint64) (b *big.Int) {
+func (mw loggingMiddleware) Fib(ctx context.Context, n uint64) (b *big.Int, err error) {
 	defer func(begin time.Time) {
 		mw.logger.Debug(
 			"service",
 			zap.String("method", "Fib"),
 			zap.Uint64("input", n),
 			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
 		)
 	}(time.Now())
-	b = mw.next.Fib(n)
+	b, err = mw.next.Fib(ctx, n)
 	return
 }
 
-func (mw instrumentingMiddleware) Fib(n uint64) (b *big.Int) {
+func (mw loggingMiddleware) FibJob(ctx context.Context, n uint64) (b *big.Int, err error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "fib", "error", "false"}
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "FibJob"),
+			zap.Uint64("input", n),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	b, err = mw.next.FibJob(ctx, n)
+	return
+}
+
+func (mw loggingMiddleware) Range(ctx context.Context, from, to uint64, emit func(n uint64, v *big.Int) error) (err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Range"),
+			zap.Uint64("from", from),
+			zap.Uint64("to", to),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	err = mw.next.Range(ctx, from, to, emit)
+	return
+}
+
+func (mw loggingMiddleware) FibMod(n, m uint64) (v uint64, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "FibMod"),
+			zap.Uint64("input", n),
+			zap.Uint64("modulus", m),
+			zap.Uint64("output", v),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	v, err = mw.next.FibMod(n, m)
+	return
+}
+
+func (mw loggingMiddleware) Pisano(m uint64) (period uint64, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Pisano"),
+			zap.Uint64("modulus", m),
+			zap.Uint64("output", period),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	period, err = mw.next.Pisano(m)
+	return
+}
+
+func (mw loggingMiddleware) Sequence(ctx context.Context, r Recurrence, n uint64) (v *big.Int, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Sequence"),
+			zap.String("name", r.Name),
+			zap.Int("k", r.K),
+			zap.Int("order", len(r.Coeffs)),
+			zap.Uint64("input", n),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	v, err = mw.next.Sequence(ctx, r, n)
+	return
+}
+
+func (mw loggingMiddleware) Index(ctx context.Context, x *big.Int) (n uint64, ok bool, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Index"),
+			zap.Int("bits", x.BitLen()),
+			zap.Uint64("output", n),
+			zap.Bool("fibonacci", ok),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	n, ok, err = mw.next.Index(ctx, x)
+	return
+}
+
+func (mw loggingMiddleware) Zeckendorf(ctx context.Context, x *big.Int) (indices []uint64, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Zeckendorf"),
+			zap.Int("bits", x.BitLen()),
+			zap.Int("terms", len(indices)),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	indices, err = mw.next.Zeckendorf(ctx, x)
+	return
+}
+
+func (mw instrumentingMiddleware) Fib(ctx context.Context, n uint64) (b *big.Int, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "fib", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	b, err = mw.next.Fib(ctx, n)
+	return
+}
+
+func (mw instrumentingMiddleware) FibJob(ctx context.Context, n uint64) (b *big.Int, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "fibjob", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	b, err = mw.next.FibJob(ctx, n)
+	return
+}
+
+func (mw instrumentingMiddleware) Range(ctx context.Context, from, to uint64, emit func(n uint64, v *big.Int) error) (err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "range", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	err = mw.next.Range(ctx, from, to, emit)
+	return
+}
+
+func (mw instrumentingMiddleware) FibMod(n, m uint64) (v uint64, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "fibmod", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	v, err = mw.next.FibMod(n, m)
+	return
+}
+
+func (mw instrumentingMiddleware) Pisano(m uint64) (period uint64, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "pisano", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	period, err = mw.next.Pisano(m)
+	return
+}
+
+func (mw instrumentingMiddleware) Sequence(ctx context.Context, r Recurrence, n uint64) (v *big.Int, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "sequence", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	v, err = mw.next.Sequence(ctx, r, n)
+	return
+}
+
+func (mw instrumentingMiddleware) Index(ctx context.Context, x *big.Int) (n uint64, ok bool, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "index", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	n, ok, err = mw.next.Index(ctx, x)
+	return
+}
+
+func (mw instrumentingMiddleware) Zeckendorf(ctx context.Context, x *big.Int) (indices []uint64, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "zeckendorf", "error", fmt.Sprint(err != nil)}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
-	b = mw.next.Fib(n)
+	indices, err = mw.next.Zeckendorf(ctx, x)
 	return
 }
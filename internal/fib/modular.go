@@ -0,0 +1,110 @@
+package fib
+
+import (
+	"math/bits"
+)
+
+// maxPisanoModulus bounds the moduli whose Pisano period is computed, as
+// both m and the candidate periods of its prime factors are factored by
+// trial division.
+const maxPisanoModulus = 1_000_000_000_000
+
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+func addMod(a, b, m uint64) uint64 {
+	s, carry := bits.Add64(a, b, 0)
+	if carry != 0 || s >= m {
+		s -= m
+	}
+	return s
+}
+
+// fibPairMod returns F(n) and F(n+1) modulo m by the same fast doubling as
+// fibPair, without ever holding more than two residues.
+func fibPairMod(n, m uint64) (uint64, uint64) {
+	a, b := uint64(0), 1%m
+	for i := bits.Len64(n) - 1; i >= 0; i-- {
+		c := mulMod(a, addMod(b, addMod(b, m-a, m), m), m)
+		d := addMod(mulMod(a, a, m), mulMod(b, b, m), m)
+		a, b = c, d
+		if n>>uint(i)&1 == 1 {
+			a, b = b, addMod(c, d, m)
+		}
+	}
+	return a, b
+}
+
+type primePower struct {
+	p uint64
+	k int
+}
+
+// factor returns the prime factorization of n by trial division.
+func factor(n uint64) []primePower {
+	var fs []primePower
+	for p := uint64(2); p*p <= n; p++ {
+		if n%p != 0 {
+			continue
+		}
+		k := 0
+		for n%p == 0 {
+			n /= p
+			k++
+		}
+		fs = append(fs, primePower{p, k})
+	}
+	if n > 1 {
+		fs = append(fs, primePower{n, 1})
+	}
+	return fs
+}
+
+// pisanoPrime returns the Pisano period of the prime p, which divides p-1
+// when p is ±1 mod 10 and 2(p+1) when p is ±3 mod 10. The candidate is
+// reduced by each of its prime factors while it remains a period.
+func pisanoPrime(p uint64) uint64 {
+	switch p {
+	case 2:
+		return 3
+	case 5:
+		return 20
+	}
+	period := 2 * (p + 1)
+	if r := p % 10; r == 1 || r == 9 {
+		period = p - 1
+	}
+	for _, f := range factor(period) {
+		for i := 0; i < f.k; i++ {
+			if a, b := fibPairMod(period/f.p, p); a != 0 || b != 1 {
+				break
+			}
+			period /= f.p
+		}
+	}
+	return period
+}
+
+// pisano returns the Pisano period of m as the lcm of the periods of its
+// prime power factors, taking π(p^k) = p^(k-1)·π(p). That identity holds for
+// every prime checked so far; a counterexample would be a Wall–Sun–Sun prime.
+func pisano(m uint64) uint64 {
+	period := uint64(1)
+	for _, f := range factor(m) {
+		pp := pisanoPrime(f.p)
+		for i := 1; i < f.k; i++ {
+			pp *= f.p
+		}
+		period = period / gcd(period, pp) * pp
+	}
+	return period
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
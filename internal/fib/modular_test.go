@@ -0,0 +1,60 @@
+package fib
+
+import (
+	"math/big"
+	"testing"
+)
+
+// naivePisano steps through the sequence mod m until 0, 1 comes round again.
+func naivePisano(m uint64) uint64 {
+	if m == 1 {
+		return 1
+	}
+	a, b := uint64(0), uint64(1)
+	for i := uint64(1); ; i++ {
+		a, b = b, (a+b)%m
+		if a == 0 && b == 1 {
+			return i
+		}
+	}
+}
+
+func TestPisano(t *testing.T) {
+	tests := []struct {
+		m, want uint64
+	}{
+		{1, 1},
+		{2, 3},
+		{3, 8},
+		{5, 20},
+		{10, 60},
+		{100, 300},
+		{1000, 1500},
+		{1000000007, 2000000016},
+	}
+	for _, tt := range tests {
+		if got := pisano(tt.m); got != tt.want {
+			t.Errorf("pisano(%d) = %d, want %d", tt.m, got, tt.want)
+		}
+	}
+	for m := uint64(1); m <= 500; m++ {
+		if got, want := pisano(m), naivePisano(m); got != want {
+			t.Errorf("pisano(%d) = %d, want %d", m, got, want)
+		}
+	}
+}
+
+func TestFibPairMod(t *testing.T) {
+	fs := iterative(301)
+	for _, m := range []uint64{1, 2, 7, 10, 1 << 40, 1<<64 - 1} {
+		bm := new(big.Int).SetUint64(m)
+		for n := 0; n <= 300; n++ {
+			a, b := fibPairMod(uint64(n), m)
+			wa := new(big.Int).Mod(fs[n], bm).Uint64()
+			wb := new(big.Int).Mod(fs[n+1], bm).Uint64()
+			if a != wa || b != wb {
+				t.Fatalf("fibPairMod(%d, %d) = %d, %d, want %d, %d", n, m, a, b, wa, wb)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package fib
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+// maxOrder bounds the order of a recurrence, since each matrix product
+// takes order³ big multiplications.
+const maxOrder = 32
+
+// Recurrence is a linear recurrence
+//
+//	a(n) = Coeffs[0]·a(n-1) + Coeffs[1]·a(n-2) + … + Coeffs[d-1]·a(n-d)
+//
+// starting from a(0) … a(d-1) = Seeds. Name selects a known sequence
+// instead, with K the order of the k-bonacci numbers.
+type Recurrence struct {
+	Name   string
+	K      int
+	Coeffs []*big.Int
+	Seeds  []*big.Int
+}
+
+// resolve fills in the coefficients and seeds of a named sequence and
+// checks the recurrence is well formed.
+func (r Recurrence) resolve() (Recurrence, error) {
+	ints := func(vs ...int64) []*big.Int {
+		bs := make([]*big.Int, len(vs))
+		for i, v := range vs {
+			bs[i] = big.NewInt(v)
+		}
+		return bs
+	}
+	switch strings.ToLower(r.Name) {
+	case "fibonacci":
+		r.Coeffs, r.Seeds = ints(1, 1), ints(0, 1)
+	case "lucas":
+		r.Coeffs, r.Seeds = ints(1, 1), ints(2, 1)
+	case "pell":
+		r.Coeffs, r.Seeds = ints(2, 1), ints(0, 1)
+	case "jacobsthal":
+		r.Coeffs, r.Seeds = ints(1, 2), ints(0, 1)
+	case "tribonacci":
+		r.Coeffs, r.Seeds = ints(1, 1, 1), ints(0, 0, 1)
+	case "kbonacci":
+		if r.K < 2 || r.K > maxOrder {
+			return r, BadRecurrence
+		}
+		r.Coeffs, r.Seeds = make([]*big.Int, r.K), make([]*big.Int, r.K)
+		for i := range r.Coeffs {
+			r.Coeffs[i], r.Seeds[i] = big.NewInt(1), big.NewInt(0)
+		}
+		r.Seeds[r.K-1].SetInt64(1)
+	case "linear":
+		if len(r.Coeffs) == 0 || len(r.Coeffs) > maxOrder || len(r.Coeffs) != len(r.Seeds) {
+			return r, BadRecurrence
+		}
+	default:
+		return r, BadSequence
+	}
+	return r, nil
+}
+
+// growth bounds the number of bits a(n) gains per step by the log of the
+// sum of the absolute coefficients, which is at least the largest root of
+// the characteristic polynomial. It is never taken below one, so that a
+// budget of n steps allows F(n).
+func (r Recurrence) growth() float64 {
+	sum := new(big.Int)
+	for _, c := range r.Coeffs {
+		sum.Add(sum, new(big.Int).Abs(c))
+	}
+	f, _ := new(big.Float).SetInt(sum).Float64()
+	return math.Max(1, math.Log2(f))
+}
+
+func (r Recurrence) seedBits() int {
+	b := 0
+	for _, s := range r.Seeds {
+		if s.BitLen() > b {
+			b = s.BitLen()
+		}
+	}
+	return b
+}
+
+// cost estimates the work of computing a(n) in units of F(n): the size of
+// the terms times the order³ products of each matrix step, scaled so that
+// an order two recurrence such as Fibonacci costs its size.
+func (r Recurrence) cost(n uint64) float64 {
+	d := float64(len(r.Coeffs))
+	bits := float64(n)*r.growth() + float64(r.seedBits())
+	return bits * math.Max(1, d*d*d/8)
+}
+
+type matrix [][]*big.Int
+
+func newMatrix(d int) matrix {
+	m := make(matrix, d)
+	for i := range m {
+		m[i] = make([]*big.Int, d)
+		for j := range m[i] {
+			m[i][j] = new(big.Int)
+		}
+	}
+	return m
+}
+
+func (m matrix) mul(ctx context.Context, o matrix) (matrix, error) {
+	p := newMatrix(len(m))
+	t := new(big.Int)
+	for i := range m {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for k := range o {
+			if m[i][k].Sign() == 0 {
+				continue
+			}
+			for j := range o[k] {
+				p[i][j].Add(p[i][j], t.Mul(m[i][k], o[k][j]))
+			}
+		}
+	}
+	return p, nil
+}
+
+// term returns a(n) by raising the companion matrix of r to the power
+// n-d+1 and applying it to the seeds.
+func (r Recurrence) term(ctx context.Context, n uint64) (*big.Int, error) {
+	d := len(r.Coeffs)
+	if n < uint64(d) {
+		return new(big.Int).Set(r.Seeds[n]), nil
+	}
+
+	// the companion matrix maps (a(i+d-1), …, a(i)) to (a(i+d), …, a(i+1))
+	c := newMatrix(d)
+	for j, coeff := range r.Coeffs {
+		c[0][j].Set(coeff)
+	}
+	for i := 1; i < d; i++ {
+		c[i][i-1].SetInt64(1)
+	}
+
+	e := n - uint64(d) + 1
+	var p matrix
+	for i := bits.Len64(e) - 1; i >= 0; i-- {
+		if p == nil {
+			p = c
+			continue
+		}
+		var err error
+		if p, err = p.mul(ctx, p); err != nil {
+			return nil, err
+		}
+		if e>>uint(i)&1 == 1 {
+			if p, err = p.mul(ctx, c); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	v, t := new(big.Int), new(big.Int)
+	for j := 0; j < d; j++ {
+		v.Add(v, t.Mul(p[0][j], r.Seeds[d-1-j]))
+	}
+	return v, nil
+}
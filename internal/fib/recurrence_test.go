@@ -0,0 +1,79 @@
+package fib
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestRecurrence(t *testing.T) {
+	fs := iterative(201)
+	lucas := func(n int) *big.Int {
+		if n == 0 {
+			return big.NewInt(2)
+		}
+		return new(big.Int).Add(fs[n-1], fs[n+1])
+	}
+	tests := []struct {
+		name string
+		r    Recurrence
+		want func(n int) *big.Int
+	}{
+		{"fibonacci", Recurrence{Name: "fibonacci"}, func(n int) *big.Int { return fs[n] }},
+		{"lucas", Recurrence{Name: "lucas"}, lucas},
+		{"linear fibonacci", Recurrence{
+			Name:   "linear",
+			Coeffs: []*big.Int{big.NewInt(1), big.NewInt(1)},
+			Seeds:  []*big.Int{big.NewInt(0), big.NewInt(1)},
+		}, func(n int) *big.Int { return fs[n] }},
+		{"linear lucas", Recurrence{
+			Name:   "linear",
+			Coeffs: []*big.Int{big.NewInt(1), big.NewInt(1)},
+			Seeds:  []*big.Int{big.NewInt(2), big.NewInt(1)},
+		}, lucas},
+	}
+	for _, tt := range tests {
+		r, err := tt.r.resolve()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for n := 0; n <= 200; n++ {
+			v, err := r.term(context.Background(), uint64(n))
+			if err != nil {
+				t.Fatalf("%s: term(%d): %v", tt.name, n, err)
+			}
+			if want := tt.want(n); v.Cmp(want) != 0 {
+				t.Errorf("%s: term(%d) = %s, want %s", tt.name, n, v, want)
+			}
+		}
+	}
+}
+
+func TestTribonacci(t *testing.T) {
+	r, err := Recurrence{Name: "tribonacci"}.resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{0, 0, 1, 1, 2, 4, 7, 13, 24, 44, 81, 149}
+	for n, w := range want {
+		v, err := r.term(context.Background(), uint64(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Int64() != w {
+			t.Errorf("term(%d) = %s, want %d", n, v, w)
+		}
+	}
+}
+
+func TestRecurrenceCancelled(t *testing.T) {
+	r, err := Recurrence{Name: "kbonacci", K: 8}.resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.term(ctx, 1000); err != context.Canceled {
+		t.Errorf("term with a cancelled context: got %v, want %v", err, context.Canceled)
+	}
+}
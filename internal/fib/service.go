@@ -1,32 +1,208 @@
 package fib
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/big"
+	"math/bits"
+
+	"github.com/go-kit/kit/metrics"
 )
 
 type Service interface {
-	Fib(uint64) *big.Int
+	Fib(ctx context.Context, n uint64) (*big.Int, error)
+	FibJob(ctx context.Context, n uint64) (*big.Int, error)
+	Range(ctx context.Context, from, to uint64, emit func(n uint64, v *big.Int) error) error
+	FibMod(n, m uint64) (uint64, error)
+	Pisano(m uint64) (uint64, error)
+	Sequence(ctx context.Context, r Recurrence, n uint64) (*big.Int, error)
+	Index(ctx context.Context, x *big.Int) (uint64, bool, error)
+	Zeckendorf(ctx context.Context, x *big.Int) ([]uint64, error)
+}
+
+var (
+	BadRange       = errors.New("bad range in request")
+	BadModulus     = errors.New("bad modulus in request")
+	NumberTooLarge = errors.New("number too large")
+	BadSequence    = errors.New("unknown sequence")
+	BadRecurrence  = errors.New("bad recurrence in request")
+)
+
+// Config holds the limits of a Fibonacci service.
+type Config struct {
+	// CacheBytes is the total size of results kept in the cache.
+	CacheBytes int
+	// MaxSpan is the largest number of values Range will produce.
+	MaxSpan uint64
+	// MaxN is the largest index Fib and Range will compute.
+	MaxN uint64
+	// MaxJobN is the largest index FibJob will compute. Jobs run in the
+	// background, so it may be well above MaxN.
+	MaxJobN uint64
 }
 
 type service struct {
-	cache [2]*big.Int
+	cache   *cache
+	maxSpan uint64
+	maxN    uint64
+	maxJobN uint64
+}
+
+func (svc *service) checkN(n uint64) error {
+	if n > svc.maxN {
+		return fmt.Errorf("%w: maximum is %d", NumberTooLarge, svc.maxN)
+	}
+	return nil
+}
+
+// Fib returns F(n). Results are shared with the cache and must not be
+// modified.
+func (svc *service) Fib(ctx context.Context, n uint64) (*big.Int, error) {
+	if err := svc.checkN(n); err != nil {
+		return nil, err
+	}
+	return svc.fib(ctx, n)
 }
 
-func (svc service) Fib(n uint64) *big.Int {
-	if n == 0 {
-		return big.NewInt(0)
-	} else if n == 1 {
-		return big.NewInt(1)
+// FibJob is Fib for background jobs, allowing n up to MaxJobN.
+func (svc *service) FibJob(ctx context.Context, n uint64) (*big.Int, error) {
+	if n > svc.maxJobN {
+		return nil, fmt.Errorf("%w: maximum is %d", NumberTooLarge, svc.maxJobN)
 	}
+	return svc.fib(ctx, n)
+}
 
-	svc.cache = [2]*big.Int{big.NewInt(1), big.NewInt(1)}
-	for i := uint64(2); i < n; i++ {
-		svc.cache[i%2] = svc.cache[0].Add(svc.cache[0], svc.cache[1])
+func (svc *service) fib(ctx context.Context, n uint64) (*big.Int, error) {
+	if v, ok := svc.cache.get(n); ok {
+		return v, nil
+	}
+	v, _, err := fibPair(ctx, n)
+	if err != nil {
+		return nil, err
 	}
+	svc.cache.add(n, v)
+	return v, nil
+}
 
-	return svc.cache[n%2]
+// Range calls emit with F(from)..F(to) in order, computing each value only
+// once its predecessors are emitted. It stops at the first error from emit
+// or when ctx is done. Values are only valid for the duration of the call.
+func (svc *service) Range(ctx context.Context, from, to uint64, emit func(n uint64, v *big.Int) error) error {
+	if to < from || to-from >= svc.maxSpan {
+		return BadRange
+	}
+	if err := svc.checkN(to); err != nil {
+		return err
+	}
+	a, b, err := fibPair(ctx, from)
+	if err != nil {
+		return err
+	}
+	for n := from; ; n++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := emit(n, a); err != nil {
+			return err
+		}
+		if n == to {
+			return nil
+		}
+		a.Add(a, b)
+		a, b = b, a
+	}
 }
 
-func NewService() Service {
-	return &service{}
+// FibMod returns F(n) mod m without computing F(n) itself.
+func (svc *service) FibMod(n, m uint64) (uint64, error) {
+	if m == 0 {
+		return 0, BadModulus
+	}
+	v, _ := fibPairMod(n, m)
+	return v, nil
+}
+
+// Pisano returns the period of the Fibonacci sequence modulo m.
+func (svc *service) Pisano(m uint64) (uint64, error) {
+	if m == 0 || m > maxPisanoModulus {
+		return 0, BadModulus
+	}
+	return pisano(m), nil
+}
+
+// Sequence returns the nth term of the recurrence r. Terms may be no larger
+// than about F(MaxN), judged from the coefficients and seeds, and higher
+// orders get a proportionally smaller budget for their costlier steps.
+func (svc *service) Sequence(ctx context.Context, r Recurrence, n uint64) (*big.Int, error) {
+	r, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if r.cost(n) > float64(svc.maxN) {
+		return nil, fmt.Errorf("%w: maximum cost is %d", NumberTooLarge, svc.maxN)
+	}
+	return r.term(ctx, n)
+}
+
+// Index reports whether x is a Fibonacci number and, if so, its index.
+// Numbers beyond F(MaxN) are refused rather than computed.
+func (svc *service) Index(ctx context.Context, x *big.Int) (uint64, bool, error) {
+	if x.Sign() > 0 && estimateIndex(x) > svc.maxN {
+		return 0, false, fmt.Errorf("%w: maximum is F(%d)", NumberTooLarge, svc.maxN)
+	}
+	return fibIndex(ctx, x)
+}
+
+// Zeckendorf returns the indices of the Fibonacci numbers making up the
+// Zeckendorf representation of x, largest first.
+func (svc *service) Zeckendorf(ctx context.Context, x *big.Int) ([]uint64, error) {
+	if x.Sign() < 0 {
+		return nil, BadNumber
+	}
+	if x.BitLen() > maxZeckendorfBits {
+		return nil, fmt.Errorf("%w: maximum is %d bits", NumberTooLarge, maxZeckendorfBits)
+	}
+	return zeckendorf(ctx, x)
+}
+
+// fibPair returns F(n) and F(n+1) by fast doubling, walking the bits of n
+// from the most significant:
+//
+//	F(2k)   = F(k) * (2*F(k+1) - F(k))
+//	F(2k+1) = F(k)^2 + F(k+1)^2
+//
+// ctx is checked before each doubling step, the last of which costs about
+// as much as all the others together.
+func fibPair(ctx context.Context, n uint64) (*big.Int, *big.Int, error) {
+	a, b := big.NewInt(0), big.NewInt(1)
+	t := new(big.Int)
+	steps := bits.Len64(n)
+	for i := steps - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		reportProgress(ctx, steps-1-i, steps)
+		// t = 2b - a; a, b = a*t, a^2 + b^2
+		t.Lsh(b, 1).Sub(t, a).Mul(t, a)
+		a.Mul(a, a)
+		b.Mul(b, b).Add(b, a)
+		a, t = t, a
+		if n>>uint(i)&1 == 1 {
+			a.Add(a, b)
+			a, b = b, a
+		}
+	}
+	return a, b, nil
+}
+
+// NewService returns a Fibonacci service limited by cfg, counting cache hits
+// and misses.
+func NewService(cfg Config, hits, misses metrics.Counter) Service {
+	return &service{
+		cache:   newCache(cfg.CacheBytes, hits, misses),
+		maxSpan: cfg.MaxSpan,
+		maxN:    cfg.MaxN,
+		maxJobN: cfg.MaxJobN,
+	}
 }
@@ -0,0 +1,79 @@
+package fib
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/go-kit/kit/metrics/discard"
+)
+
+// iterative returns F(0)..F(n) by repeated addition.
+func iterative(n int) []*big.Int {
+	fs := []*big.Int{big.NewInt(0), big.NewInt(1)}
+	for i := 2; i <= n; i++ {
+		fs = append(fs, new(big.Int).Add(fs[i-1], fs[i-2]))
+	}
+	return fs[:n+1]
+}
+
+func newTestService() Service {
+	return NewService(
+		Config{CacheBytes: 1 << 20, MaxSpan: 1000, MaxN: 1 << 20, MaxJobN: 1 << 20},
+		discard.NewCounter(),
+		discard.NewCounter(),
+	)
+}
+
+func TestFibPair(t *testing.T) {
+	want := iterative(101)
+	for n := 0; n <= 100; n++ {
+		a, b, err := fibPair(context.Background(), uint64(n))
+		if err != nil {
+			t.Fatalf("fibPair(%d): %v", n, err)
+		}
+		if a.Cmp(want[n]) != 0 || b.Cmp(want[n+1]) != 0 {
+			t.Errorf("fibPair(%d) = %s, %s, want %s, %s", n, a, b, want[n], want[n+1])
+		}
+	}
+}
+
+func TestFibLarge(t *testing.T) {
+	svc := newTestService()
+	tests := []struct {
+		n    uint64
+		want *big.Int
+	}{
+		{100, mustInt("354224848179261915075")},
+		{20000, iterative(20000)[20000]},
+	}
+	for _, tt := range tests {
+		v, err := svc.Fib(context.Background(), tt.n)
+		if err != nil {
+			t.Fatalf("Fib(%d): %v", tt.n, err)
+		}
+		if v.Cmp(tt.want) != 0 {
+			t.Errorf("Fib(%d) is wrong", tt.n)
+		}
+	}
+}
+
+func TestFibLimits(t *testing.T) {
+	svc := newTestService()
+	if _, err := svc.Fib(context.Background(), 1<<20+1); err == nil {
+		t.Error("Fib past MaxN: got nil error")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := svc.Fib(ctx, 1000); err != context.Canceled {
+		t.Errorf("Fib with a cancelled context: got %v, want %v", err, context.Canceled)
+	}
+}
+
+func mustInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("bad integer " + s)
+	}
+	return v
+}
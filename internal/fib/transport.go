@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
@@ -14,15 +17,61 @@ import (
 var BadNumber = errors.New("bad number in request")
 
 func MakeHandler(ss Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
 	fibHandler := kithttp.NewServer(
 		makeFibEndpoint(ss),
 		decodeFibRequest,
 		encodeResponse,
+		opts...,
+	)
+
+	fibModHandler := kithttp.NewServer(
+		makeFibModEndpoint(ss),
+		decodeFibModRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	pisanoHandler := kithttp.NewServer(
+		makePisanoEndpoint(ss),
+		decodePisanoRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	sequenceHandler := kithttp.NewServer(
+		makeSequenceEndpoint(ss),
+		decodeSequenceRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	indexHandler := kithttp.NewServer(
+		makeIndexEndpoint(ss),
+		decodeNumberRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	zeckendorfHandler := kithttp.NewServer(
+		makeZeckendorfEndpoint(ss),
+		decodeNumberRequest,
+		encodeResponse,
+		opts...,
 	)
 
 	r := mux.NewRouter()
 
+	r.Path("/fib/index").Handler(indexHandler).Methods("POST")
+	r.Path("/fib/zeckendorf").Handler(zeckendorfHandler).Methods("POST")
+	r.Path("/fib/pisano/{m}").Handler(pisanoHandler).Methods("GET")
+	r.Path("/fib/{n}/mod/{m}").Handler(fibModHandler).Methods("GET")
 	r.Path("/fib/{n}").Handler(fibHandler).Methods("GET")
+	r.Path("/fib/{from}/{to}").Handler(makeRangeHandler(ss)).Methods("GET")
+	r.Path("/seq/{name}/{n}").Handler(sequenceHandler).Methods("GET")
 
 	return r
 }
@@ -38,9 +87,159 @@ func decodeFibRequest(ctx context.Context, r *http.Request) (interface{}, error)
 	if err != nil {
 		return nil, BadNumber
 	}
-	return fibRequest{N: n}, nil
+	q := r.URL.Query()
+	format, err := parseFormat(q.Get("format"), q.Get("base"), q.Get("k"))
+	if err != nil {
+		return nil, err
+	}
+	return fibRequest{N: n, Format: format}, nil
+}
+
+func decodeFibModRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	n, err := strconv.ParseUint(vars["n"], 10, 0)
+	if err != nil {
+		return nil, BadNumber
+	}
+	m, err := strconv.ParseUint(vars["m"], 10, 0)
+	if err != nil {
+		return nil, BadModulus
+	}
+	return fibModRequest{N: n, M: m}, nil
+}
+
+func decodePisanoRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	m, err := strconv.ParseUint(mux.Vars(r)["m"], 10, 0)
+	if err != nil {
+		return nil, BadModulus
+	}
+	return pisanoRequest{M: m}, nil
+}
+
+func decodeNumberRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var request numberRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadNumber
+	}
+	return request, nil
+}
+
+// decodeSequenceRequest reads the order of the k-bonacci numbers, and comma
+// separated coeffs and seeds for a linear recurrence, from the query string
+// alongside the format options of /fib/{n}.
+func decodeSequenceRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	vars := mux.Vars(r)
+	n, err := strconv.ParseUint(vars["n"], 10, 0)
+	if err != nil {
+		return nil, BadNumber
+	}
+	q := r.URL.Query()
+	rec := Recurrence{Name: vars["name"]}
+	if order := q.Get("order"); order != "" {
+		if rec.K, err = strconv.Atoi(order); err != nil {
+			return nil, BadRecurrence
+		}
+	}
+	if rec.Coeffs, err = parseInts(q.Get("coeffs")); err != nil {
+		return nil, err
+	}
+	if rec.Seeds, err = parseInts(q.Get("seeds")); err != nil {
+		return nil, err
+	}
+	format, err := parseFormat(q.Get("format"), q.Get("base"), q.Get("k"))
+	if err != nil {
+		return nil, err
+	}
+	return sequenceRequest{Recurrence: rec, N: n, Format: format}, nil
+}
+
+func parseInts(s string) ([]*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	parts := strings.Split(s, ",")
+	if len(parts) > maxOrder {
+		return nil, BadRecurrence
+	}
+	vs := make([]*big.Int, len(parts))
+	for i, p := range parts {
+		v, ok := new(big.Int).SetString(strings.TrimSpace(p), 10)
+		if !ok {
+			return nil, BadRecurrence
+		}
+		vs[i] = v
+	}
+	return vs, nil
+}
+
+// streamTimeout is how long a range stream may go without writing a value.
+// It replaces the server's whole-request write timeout.
+const streamTimeout = 10 * time.Second
+
+type rangeLine struct {
+	N   uint64 `json:"n"`
+	Fib string `json:"fib"`
+}
+
+// makeRangeHandler streams a range of Fibonacci numbers as newline-delimited
+// JSON, flushing each line as it is computed. The stream ends early when the
+// client goes away, and is aborted if computing a value fails part way.
+func makeRangeHandler(ss Service) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		from, err1 := strconv.ParseUint(vars["from"], 10, 0)
+		to, err2 := strconv.ParseUint(vars["to"], 10, 0)
+		if err1 != nil || err2 != nil {
+			encodeError(r.Context(), BadNumber, w)
+			return
+		}
+
+		rc := http.NewResponseController(w)
+		enc := json.NewEncoder(w)
+		started := false
+		err := ss.Range(r.Context(), from, to, func(n uint64, v *big.Int) error {
+			if !started {
+				w.Header().Set("Content-Type", "application/x-ndjson")
+				started = true
+			}
+			rc.SetWriteDeadline(time.Now().Add(streamTimeout))
+			if err := enc.Encode(rangeLine{n, v.String()}); err != nil {
+				return err
+			}
+			return rc.Flush()
+		})
+		if err != nil {
+			if !started {
+				encodeError(r.Context(), err, w)
+				return
+			}
+			// the status line is already sent, so abort the response to
+			// keep the client from mistaking it for a complete one
+			panic(http.ErrAbortHandler)
+		}
+	})
 }
 
 func encodeResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
 	return json.NewEncoder(w).Encode(response)
 }
+
+// encodeError writes err as JSON with a status code matching its cause.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch {
+	case errors.Is(err, BadNumber), errors.Is(err, BadRange),
+		errors.Is(err, BadModulus), errors.Is(err, BadFormat),
+		errors.Is(err, BadSequence), errors.Is(err, BadRecurrence):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(err, NumberTooLarge):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"err": err.Error(),
+	})
+}
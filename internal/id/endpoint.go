@@ -0,0 +1,49 @@
+package id
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type generateRequest struct {
+	Kind string
+	N    int
+}
+
+type generateResponse struct {
+	IDs []string `json:"ids,omitempty"`
+	Err string   `json:"err,omitempty"`
+}
+
+type inspectRequest struct {
+	ID string
+}
+
+type inspectResponse struct {
+	Info
+	Valid bool   `json:"valid"`
+	Err   string `json:"err,omitempty"`
+}
+
+func makeGenerateEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(generateRequest)
+		ids, err := svc.Generate(req.Kind, req.N)
+		if err != nil {
+			return generateResponse{nil, err.Error()}, nil
+		}
+		return generateResponse{ids, ""}, nil
+	}
+}
+
+func makeInspectEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(inspectRequest)
+		info, err := svc.Inspect(req.ID)
+		if err != nil {
+			return inspectResponse{Err: err.Error()}, nil
+		}
+		return inspectResponse{info, true, ""}, nil
+	}
+}
@@ -0,0 +1,163 @@
+package id
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ksuidEpoch is the KSUID timestamp origin, 2014-05-13T16:53:20Z.
+const ksuidEpoch = 1400000000
+
+// gregorianOffset is the number of 100ns intervals between the UUID v1 and
+// v6 epoch, 1582-10-15, and the Unix epoch.
+const gregorianOffset = 0x01b21dd213814000
+
+func formatUUID(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// parseUUID accepts the canonical hyphenated form, optionally braced or
+// prefixed with "urn:uuid:", and the bare 32 digit hex form.
+func parseUUID(s string) ([16]byte, bool) {
+	var b [16]byte
+	s = strings.TrimPrefix(strings.ToLower(s), "urn:uuid:")
+	if len(s) == 38 && s[0] == '{' && s[37] == '}' {
+		s = s[1:37]
+	}
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return b, false
+		}
+		s = s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(s) != 32 {
+		return b, false
+	}
+	if _, err := hex.Decode(b[:], []byte(s)); err != nil {
+		return b, false
+	}
+	return b, true
+}
+
+// uuidTime extracts the timestamp of time-based UUID versions.
+func uuidTime(b [16]byte) (time.Time, bool) {
+	switch b[6] >> 4 {
+	case 1:
+		ts := uint64(b[0])<<24 | uint64(b[1])<<16 | uint64(b[2])<<8 | uint64(b[3]) |
+			uint64(b[4])<<40 | uint64(b[5])<<32 |
+			uint64(b[6]&0x0f)<<56 | uint64(b[7])<<48
+		return gregorianTime(ts), true
+	case 6:
+		ts := uint64(b[0])<<52 | uint64(b[1])<<44 | uint64(b[2])<<36 | uint64(b[3])<<28 |
+			uint64(b[4])<<20 | uint64(b[5])<<12 |
+			uint64(b[6]&0x0f)<<8 | uint64(b[7])
+		return gregorianTime(ts), true
+	case 7:
+		ms := uint64(b[0])<<40 | uint64(b[1])<<32 | uint64(b[2])<<24 |
+			uint64(b[3])<<16 | uint64(b[4])<<8 | uint64(b[5])
+		return time.UnixMilli(int64(ms)).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+func gregorianTime(ts uint64) time.Time {
+	unix100ns := int64(ts) - gregorianOffset
+	return time.Unix(0, unix100ns*100).UTC()
+}
+
+// formatULID encodes 128 bits as 26 Crockford base32 digits, the first of
+// which only carries three bits.
+func formatULID(b [16]byte) string {
+	hi := uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32 |
+		uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])
+	lo := uint64(b[8])<<56 | uint64(b[9])<<48 | uint64(b[10])<<40 | uint64(b[11])<<32 |
+		uint64(b[12])<<24 | uint64(b[13])<<16 | uint64(b[14])<<8 | uint64(b[15])
+	var buf [26]byte
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+func parseULID(s string) ([16]byte, bool) {
+	var b [16]byte
+	if len(s) != 26 {
+		return b, false
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		switch c {
+		case 'O':
+			c = '0'
+		case 'I', 'L':
+			c = '1'
+		}
+		v := strings.IndexByte(crockford, c)
+		if v < 0 || (i == 0 && v > 7) {
+			return b, false
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	for i := 0; i < 8; i++ {
+		b[i] = byte(hi >> (56 - 8*i))
+		b[8+i] = byte(lo >> (56 - 8*i))
+	}
+	return b, true
+}
+
+// formatKSUID encodes 20 bytes as 27 base62 digits, zero padded.
+func formatKSUID(b [20]byte) string {
+	n := new(big.Int).SetBytes(b[:])
+	var buf [27]byte
+	base := big.NewInt(62)
+	mod := new(big.Int)
+	for i := 26; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		buf[i] = base62[mod.Int64()]
+	}
+	return string(buf[:])
+}
+
+func parseKSUID(s string) ([20]byte, bool) {
+	var b [20]byte
+	if len(s) != 27 {
+		return b, false
+	}
+	n := new(big.Int)
+	base := big.NewInt(62)
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(base62, s[i])
+		if v < 0 {
+			return b, false
+		}
+		n.Mul(n, base).Add(n, big.NewInt(int64(v)))
+	}
+	if n.BitLen() > 160 {
+		return b, false
+	}
+	n.FillBytes(b[:])
+	return b, true
+}
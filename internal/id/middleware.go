@@ -0,0 +1,94 @@
+package id
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"go.uber.org/zap"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger zap.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+func NewInstrumentingMiddleware(
+	counter metrics.Counter,
+	latency metrics.Histogram,
+	s Service,
+) Service {
+	return &instrumentingMiddleware{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger zap.Logger
+}
+
+type instrumentingMiddleware struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+func (mw loggingMiddleware) Generate(kind string, n int) (ids []string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Generate"),
+			zap.String("kind", kind),
+			zap.Int("n", n),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	ids, err = mw.next.Generate(kind, n)
+	return
+}
+
+func (mw loggingMiddleware) Inspect(id string) (info Info, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Inspect"),
+			zap.String("input", id),
+			zap.Any("output", info),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	info, err = mw.next.Inspect(id)
+	return
+}
+
+func (mw instrumentingMiddleware) Generate(kind string, n int) (ids []string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "generate", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	ids, err = mw.next.Generate(kind, n)
+	return
+}
+
+func (mw instrumentingMiddleware) Inspect(id string) (info Info, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "inspect", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	info, err = mw.next.Inspect(id)
+	return
+}
@@ -0,0 +1,201 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Service interface {
+	Generate(kind string, n int) ([]string, error)
+	Inspect(id string) (Info, error)
+}
+
+// Info describes a parsed identifier. Timestamp is only set for kinds and
+// versions that embed one.
+type Info struct {
+	Kind      string     `json:"kind,omitempty"`
+	Version   int        `json:"version,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+const maxGenerate = 1000
+
+var (
+	ErrKind    = errors.New("Unknown ID type")
+	ErrCount   = errors.New("Count out of range")
+	ErrInvalid = errors.New("Unrecognised ID")
+)
+
+// counter is the random part of an ID that is incremented, rather than
+// redrawn, when several IDs share a millisecond. bits is its width.
+type counter struct {
+	hi   uint16
+	lo   uint64
+	bits uint
+}
+
+// inc adds one, reporting false if the counter overflowed its width.
+func (c *counter) inc() bool {
+	c.lo++
+	if c.lo != 0 {
+		return true
+	}
+	c.hi++
+	return c.hi != 0 && c.hi>>(c.bits-64) == 0
+}
+
+func (c *counter) reseed() error {
+	var b [10]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return err
+	}
+	c.hi = binary.BigEndian.Uint16(b[:2]) & (1<<(c.bits-64) - 1)
+	c.lo = binary.BigEndian.Uint64(b[2:])
+	return nil
+}
+
+// monotonic hands out millisecond timestamps and counters that strictly
+// increase across calls, even if the clock stalls or steps backwards.
+type monotonic struct {
+	mu     sync.Mutex
+	lastMs int64
+	c      counter
+}
+
+func (m *monotonic) next() (int64, counter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ms := time.Now().UnixMilli()
+	if ms > m.lastMs {
+		m.lastMs = ms
+		if err := m.c.reseed(); err != nil {
+			return 0, counter{}, err
+		}
+		return m.lastMs, m.c, nil
+	}
+	if !m.c.inc() {
+		// borrow the next millisecond rather than wrap around
+		m.lastMs++
+		if err := m.c.reseed(); err != nil {
+			return 0, counter{}, err
+		}
+	}
+	return m.lastMs, m.c, nil
+}
+
+type service struct {
+	v7   monotonic
+	ulid monotonic
+}
+
+// Generate returns n new IDs of the given kind: uuid4, uuid7, ulid or
+// ksuid. UUIDv7s and ULIDs sort in generation order within this process.
+func (svc *service) Generate(kind string, n int) ([]string, error) {
+	if n <= 0 || n > maxGenerate {
+		return nil, ErrCount
+	}
+	var gen func() (string, error)
+	switch strings.ToLower(kind) {
+	case "uuid4", "uuidv4":
+		gen = newUUIDv4
+	case "uuid7", "uuidv7":
+		gen = svc.newUUIDv7
+	case "ulid":
+		gen = svc.newULID
+	case "ksuid":
+		gen = newKSUID
+	default:
+		return nil, ErrKind
+	}
+	ids := make([]string, n)
+	for i := range ids {
+		id, err := gen()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// Inspect recognises UUIDs of any version, ULIDs and KSUIDs by their
+// textual form.
+func (svc *service) Inspect(id string) (Info, error) {
+	id = strings.TrimSpace(id)
+	if b, ok := parseUUID(id); ok {
+		info := Info{Kind: "uuid", Version: int(b[6] >> 4)}
+		if b[8]&0xc0 != 0x80 {
+			// nil, max and non-RFC variants carry no meaningful version
+			info.Version = 0
+		} else if ts, ok := uuidTime(b); ok {
+			info.Timestamp = &ts
+		}
+		return info, nil
+	}
+	if b, ok := parseULID(id); ok {
+		ms := int64(binary.BigEndian.Uint64(b[:8]) >> 16)
+		ts := time.UnixMilli(ms).UTC()
+		return Info{Kind: "ulid", Timestamp: &ts}, nil
+	}
+	if b, ok := parseKSUID(id); ok {
+		ts := time.Unix(int64(binary.BigEndian.Uint32(b[:4]))+ksuidEpoch, 0).UTC()
+		return Info{Kind: "ksuid", Timestamp: &ts}, nil
+	}
+	return Info{}, ErrInvalid
+}
+
+func newUUIDv4() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return formatUUID(b), nil
+}
+
+// newUUIDv7 uses the 74 bits of rand_a and rand_b as one counter, RFC 9562
+// method 2.
+func (svc *service) newUUIDv7() (string, error) {
+	ms, c, err := svc.v7.next()
+	if err != nil {
+		return "", err
+	}
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(ms)<<16)
+	randA := c.hi<<2 | uint16(c.lo>>62)
+	binary.BigEndian.PutUint16(b[6:8], randA|0x7000)
+	binary.BigEndian.PutUint64(b[8:], c.lo&(1<<62-1)|1<<63)
+	return formatUUID(b), nil
+}
+
+func (svc *service) newULID() (string, error) {
+	ms, c, err := svc.ulid.next()
+	if err != nil {
+		return "", err
+	}
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(ms)<<16|uint64(c.hi))
+	binary.BigEndian.PutUint64(b[8:], c.lo)
+	return formatULID(b), nil
+}
+
+func newKSUID() (string, error) {
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	if _, err := rand.Read(b[4:]); err != nil {
+		return "", err
+	}
+	return formatKSUID(b), nil
+}
+
+func NewService() Service {
+	return &service{
+		v7:   monotonic{c: counter{bits: 74}},
+		ulid: monotonic{c: counter{bits: 80}},
+	}
+}
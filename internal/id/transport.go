@@ -0,0 +1,56 @@
+package id
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+var BadCount = errors.New("bad count in request")
+
+func MakeHandler(is Service) http.Handler {
+	generateHandler := kithttp.NewServer(
+		makeGenerateEndpoint(is),
+		decodeGenerateRequest,
+		encodeResponse,
+	)
+
+	inspectHandler := kithttp.NewServer(
+		makeInspectEndpoint(is),
+		decodeInspectRequest,
+		encodeResponse,
+	)
+
+	r := mux.NewRouter()
+
+	r.Path("/id/inspect/{id}").Handler(inspectHandler).Methods("GET")
+	r.Path("/id/{kind}").Handler(generateHandler).Methods("GET")
+
+	return r
+}
+
+// decodeGenerateRequest reads the number of IDs from the optional n query
+// parameter, defaulting to one.
+func decodeGenerateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	n := 1
+	if ns := r.URL.Query().Get("n"); ns != "" {
+		var err error
+		if n, err = strconv.Atoi(ns); err != nil {
+			return nil, BadCount
+		}
+	}
+	return generateRequest{Kind: mux.Vars(r)["kind"], N: n}, nil
+}
+
+func decodeInspectRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return inspectRequest{ID: mux.Vars(r)["id"]}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type submitRequest struct {
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params"`
+}
+
+type idRequest struct {
+	ID string
+}
+
+func makeSubmitEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(submitRequest)
+		return svc.Submit(req.Kind, req.Params)
+	}
+}
+
+func makeStatusEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(idRequest)
+		return svc.Status(req.ID)
+	}
+}
+
+func makeResultEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(idRequest)
+		return svc.Result(req.ID)
+	}
+}
+
+func makeDeleteEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(idRequest)
+		return svc.Delete(req.ID)
+	}
+}
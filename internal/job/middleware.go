@@ -0,0 +1,145 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"go.uber.org/zap"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger zap.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+func NewInstrumentingMiddleware(
+	counter metrics.Counter,
+	latency metrics.Histogram,
+	s Service,
+) Service {
+	return &instrumentingMiddleware{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger zap.Logger
+}
+
+type instrumentingMiddleware struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+func (mw loggingMiddleware) Submit(kind string, params json.RawMessage) (j Job, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Submit"),
+			zap.String("kind", kind),
+			zap.ByteString("params", params),
+			zap.String("id", j.ID),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	j, err = mw.next.Submit(kind, params)
+	return
+}
+
+func (mw loggingMiddleware) Status(id string) (j Job, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Status"),
+			zap.String("id", id),
+			zap.String("status", j.Status),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	j, err = mw.next.Status(id)
+	return
+}
+
+func (mw loggingMiddleware) Result(id string) (result interface{}, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Result"),
+			zap.String("id", id),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	result, err = mw.next.Result(id)
+	return
+}
+
+func (mw loggingMiddleware) Delete(id string) (j Job, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Delete"),
+			zap.String("id", id),
+			zap.String("status", j.Status),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	j, err = mw.next.Delete(id)
+	return
+}
+
+func (mw instrumentingMiddleware) Submit(kind string, params json.RawMessage) (j Job, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "submit", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	j, err = mw.next.Submit(kind, params)
+	return
+}
+
+func (mw instrumentingMiddleware) Status(id string) (j Job, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "status", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	j, err = mw.next.Status(id)
+	return
+}
+
+func (mw instrumentingMiddleware) Result(id string) (result interface{}, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "result", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	result, err = mw.next.Result(id)
+	return
+}
+
+func (mw instrumentingMiddleware) Delete(id string) (j Job, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "delete", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	j, err = mw.next.Delete(id)
+	return
+}
@@ -0,0 +1,299 @@
+package job
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+type Service interface {
+	Submit(kind string, params json.RawMessage) (Job, error)
+	Status(id string) (Job, error)
+	Result(id string) (interface{}, error)
+	Delete(id string) (Job, error)
+}
+
+// Task runs a submitted job, reporting progress between 0 and 1 as it
+// goes. It should return promptly once ctx is done.
+type Task func(ctx context.Context, progress func(float64)) (interface{}, error)
+
+// Kind validates the parameters of a job and returns the task to run.
+type Kind func(params json.RawMessage) (Task, error)
+
+const (
+	StatusQueued  = "queued"
+	StatusRunning = "running"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// Job is the state of a submitted job. Expires is set once it finishes.
+type Job struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Status    string     `json:"status"`
+	Progress  float64    `json:"progress"`
+	Err       string     `json:"err,omitempty"`
+	Submitted time.Time  `json:"submitted"`
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Expires   *time.Time `json:"expires,omitempty"`
+}
+
+var (
+	ErrKind      = errors.New("Unknown job kind")
+	ErrNotFound  = errors.New("Job not found")
+	ErrQueueFull = errors.New("Job queue is full")
+	ErrNotDone   = errors.New("Job has not finished")
+	ErrFailed    = errors.New("Job failed")
+)
+
+// Config sizes the worker pool and bounds how long jobs and their results
+// are kept.
+type Config struct {
+	// Workers is the number of jobs run at once.
+	Workers int
+	// QueueLength is the number of jobs that may wait for a worker.
+	QueueLength int
+	// Timeout cancels jobs that run for longer.
+	Timeout time.Duration
+	// ResultTTL is how long a finished job is kept.
+	ResultTTL time.Duration
+	// MaxResults is the number of finished jobs kept, beyond which the
+	// oldest are dropped before their TTL.
+	MaxResults int
+}
+
+// Defaults for Config fields left unset or out of range.
+const (
+	defaultQueueLength = 64
+	defaultTimeout     = 10 * time.Minute
+	defaultResultTTL   = 10 * time.Minute
+	defaultMaxResults  = 1000
+	// minExpireInterval keeps a tiny TTL from spinning the expiry loop.
+	minExpireInterval = time.Second
+)
+
+type job struct {
+	Job
+	task   Task
+	result interface{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type service struct {
+	cfg   Config
+	kinds map[string]Kind
+	queue chan *job
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// Submit validates params for kind and queues the job, failing if the
+// queue is full.
+func (svc *service) Submit(kind string, params json.RawMessage) (Job, error) {
+	k, ok := svc.kinds[kind]
+	if !ok {
+		return Job{}, ErrKind
+	}
+	task, err := k(params)
+	if err != nil {
+		return Job{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &job{
+		Job:    Job{ID: id, Kind: kind, Status: StatusQueued, Submitted: time.Now()},
+		task:   task,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	select {
+	case svc.queue <- j:
+	default:
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	svc.jobs[id] = j
+	return j.Job, nil
+}
+
+func (svc *service) Status(id string) (Job, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	j, ok := svc.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return j.Job, nil
+}
+
+// Result returns the value a finished job produced, or the error it failed
+// with.
+func (svc *service) Result(id string) (interface{}, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	j, ok := svc.jobs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	switch j.Status {
+	case StatusDone:
+		return j.result, nil
+	case StatusFailed:
+		return nil, fmt.Errorf("%w: %s", ErrFailed, j.Err)
+	default:
+		return nil, ErrNotDone
+	}
+}
+
+// Delete forgets a job, cancelling it if it has not finished.
+func (svc *service) Delete(id string) (Job, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	j, ok := svc.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	j.cancel()
+	delete(svc.jobs, id)
+	return j.Job, nil
+}
+
+func (svc *service) worker() {
+	for j := range svc.queue {
+		svc.run(j)
+	}
+}
+
+func (svc *service) run(j *job) {
+	svc.mu.Lock()
+	if j.ctx.Err() != nil {
+		// deleted while queued
+		svc.mu.Unlock()
+		return
+	}
+	started := time.Now()
+	j.Status = StatusRunning
+	j.Started = &started
+	svc.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(j.ctx, svc.cfg.Timeout)
+	defer cancel()
+	result, err := j.task(ctx, func(p float64) {
+		svc.mu.Lock()
+		j.Progress = p
+		svc.mu.Unlock()
+	})
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	defer svc.evict()
+	finished := time.Now()
+	expires := finished.Add(svc.cfg.ResultTTL)
+	j.Finished = &finished
+	j.Expires = &expires
+	if err != nil {
+		j.Status = StatusFailed
+		j.Err = err.Error()
+		return
+	}
+	j.Status = StatusDone
+	j.Progress = 1
+	j.result = result
+}
+
+// evict drops the oldest finished jobs while more than MaxResults are
+// kept. The caller must hold mu.
+func (svc *service) evict() {
+	for {
+		var oldest *job
+		n := 0
+		for _, j := range svc.jobs {
+			if j.Finished == nil {
+				continue
+			}
+			n++
+			if oldest == nil || j.Finished.Before(*oldest.Finished) {
+				oldest = j
+			}
+		}
+		if n <= svc.cfg.MaxResults {
+			return
+		}
+		delete(svc.jobs, oldest.ID)
+	}
+}
+
+// expire drops finished jobs whose results have outlived the TTL.
+func (svc *service) expire() {
+	interval := svc.cfg.ResultTTL / 2
+	if interval < minExpireInterval {
+		interval = minExpireInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		svc.mu.Lock()
+		for id, j := range svc.jobs {
+			if j.Expires != nil && now.After(*j.Expires) {
+				delete(svc.jobs, id)
+			}
+		}
+		svc.mu.Unlock()
+	}
+}
+
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// NewService starts a pool of workers running jobs of the given kinds.
+// Fields of cfg that are not positive take their defaults, with a worker
+// per CPU.
+func NewService(cfg Config, kinds map[string]Kind) Service {
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.QueueLength <= 0 {
+		cfg.QueueLength = defaultQueueLength
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.ResultTTL <= 0 {
+		cfg.ResultTTL = defaultResultTTL
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = defaultMaxResults
+	}
+	svc := &service{
+		cfg:   cfg,
+		kinds: kinds,
+		queue: make(chan *job, cfg.QueueLength),
+		jobs:  make(map[string]*job),
+	}
+	for i := 0; i < cfg.Workers; i++ {
+		go svc.worker()
+	}
+	go svc.expire()
+	return svc
+}
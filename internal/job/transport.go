@@ -0,0 +1,102 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+var BadRequest = errors.New("bad job in request")
+
+func MakeHandler(js Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	submitHandler := kithttp.NewServer(
+		makeSubmitEndpoint(js),
+		decodeSubmitRequest,
+		encodeSubmitResponse,
+		opts...,
+	)
+
+	statusHandler := kithttp.NewServer(
+		makeStatusEndpoint(js),
+		decodeIDRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	resultHandler := kithttp.NewServer(
+		makeResultEndpoint(js),
+		decodeIDRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	deleteHandler := kithttp.NewServer(
+		makeDeleteEndpoint(js),
+		decodeIDRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Path("/jobs").Handler(submitHandler).Methods("POST")
+	r.Path("/jobs/{id}").Handler(statusHandler).Methods("GET")
+	r.Path("/jobs/{id}").Handler(deleteHandler).Methods("DELETE")
+	r.Path("/jobs/{id}/result").Handler(resultHandler).Methods("GET")
+
+	return r
+}
+
+func decodeSubmitRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request submitRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadRequest
+	}
+	return request, nil
+}
+
+func decodeIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return idRequest{ID: mux.Vars(r)["id"]}, nil
+}
+
+// encodeSubmitResponse answers 202 Accepted, pointing at the new job.
+func encodeSubmitResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	j := response.(Job)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Location", "/jobs/"+j.ID)
+	w.WriteHeader(http.StatusAccepted)
+	return json.NewEncoder(w).Encode(j)
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeError writes err as JSON with a status code matching its cause.
+// Anything else is taken to be a kind rejecting its parameters.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch {
+	case errors.Is(err, ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, ErrNotDone):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(err, ErrFailed):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Is(err, ErrQueueFull):
+		w.WriteHeader(http.StatusServiceUnavailable)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"err": err.Error(),
+	})
+}
@@ -0,0 +1,172 @@
+package numtheory
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Numbers travel as decimal strings so that they survive JSON clients that
+// read numbers as doubles.
+
+type primeRequest struct {
+	N      string `json:"n"`
+	Rounds *int   `json:"rounds,omitempty"`
+}
+
+type primeResponse struct {
+	Prime bool `json:"prime"`
+}
+
+type factorRequest struct {
+	N      string `json:"n"`
+	Budget string `json:"budget,omitempty"`
+}
+
+type factorJSON struct {
+	Prime    string `json:"prime"`
+	Exponent int    `json:"exponent"`
+}
+
+type factorResponse struct {
+	Factors   []factorJSON `json:"factors"`
+	Composite []string     `json:"composite,omitempty"`
+	Complete  bool         `json:"complete"`
+}
+
+type gcdRequest struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+type gcdResponse struct {
+	GCD string `json:"gcd"`
+	LCM string `json:"lcm"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type modPowRequest struct {
+	Base string `json:"base"`
+	Exp  string `json:"exp"`
+	Mod  string `json:"mod"`
+}
+
+type modInverseRequest struct {
+	A   string `json:"a"`
+	Mod string `json:"mod"`
+}
+
+type valueResponse struct {
+	V string `json:"v"`
+}
+
+// defaultRounds applies when a request leaves rounds out.
+const defaultRounds = 20
+
+// parseInts parses decimal strings, failing on the first that is not one.
+func parseInts(ss ...string) ([]*big.Int, error) {
+	ns := make([]*big.Int, len(ss))
+	for i, s := range ss {
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, BadNumber
+		}
+		ns[i] = n
+	}
+	return ns, nil
+}
+
+func makePrimeEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(primeRequest)
+		ns, err := parseInts(req.N)
+		if err != nil {
+			return nil, err
+		}
+		rounds := defaultRounds
+		if req.Rounds != nil {
+			rounds = *req.Rounds
+		}
+		prime, err := svc.IsPrime(ns[0], rounds)
+		if err != nil {
+			return nil, err
+		}
+		return primeResponse{prime}, nil
+	}
+}
+
+func makeFactorEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(factorRequest)
+		ns, err := parseInts(req.N)
+		if err != nil {
+			return nil, err
+		}
+		var budget time.Duration
+		if req.Budget != "" {
+			if budget, err = time.ParseDuration(req.Budget); err != nil {
+				return nil, ErrBudget
+			}
+		}
+		fz, err := svc.Factor(ctx, ns[0], budget)
+		if err != nil {
+			return nil, err
+		}
+		resp := factorResponse{Factors: []factorJSON{}, Complete: fz.Complete}
+		for _, f := range fz.Factors {
+			resp.Factors = append(resp.Factors, factorJSON{f.Prime.String(), f.Exponent})
+		}
+		for _, c := range fz.Composite {
+			resp.Composite = append(resp.Composite, c.String())
+		}
+		return resp, nil
+	}
+}
+
+func makeGCDEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(gcdRequest)
+		ns, err := parseInts(req.A, req.B)
+		if err != nil {
+			return nil, err
+		}
+		g, err := svc.GCD(ns[0], ns[1])
+		if err != nil {
+			return nil, err
+		}
+		return gcdResponse{g.GCD.String(), g.LCM.String(), g.X.String(), g.Y.String()}, nil
+	}
+}
+
+func makeModPowEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(modPowRequest)
+		ns, err := parseInts(req.Base, req.Exp, req.Mod)
+		if err != nil {
+			return nil, err
+		}
+		v, err := svc.ModPow(ns[0], ns[1], ns[2])
+		if err != nil {
+			return nil, err
+		}
+		return valueResponse{v.String()}, nil
+	}
+}
+
+func makeModInverseEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(modInverseRequest)
+		ns, err := parseInts(req.A, req.Mod)
+		if err != nil {
+			return nil, err
+		}
+		v, err := svc.ModInverse(ns[0], ns[1])
+		if err != nil {
+			return nil, err
+		}
+		return valueResponse{v.String()}, nil
+	}
+}
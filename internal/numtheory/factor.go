@@ -0,0 +1,157 @@
+package numtheory
+
+import (
+	"context"
+	"math/big"
+	"sort"
+)
+
+// Factor is a prime factor and its multiplicity.
+type Factor struct {
+	Prime    *big.Int
+	Exponent int
+}
+
+// Factorization is the prime factorization found within the time budget.
+// When Complete is false, Composite holds the cofactors that could not be
+// split; their product with the factors is still n.
+type Factorization struct {
+	Factors   []Factor
+	Composite []*big.Int
+	Complete  bool
+}
+
+// trialLimit is the bound below which factors are found by trial division.
+const trialLimit = 10000
+
+// primeRounds is the number of Miller-Rabin rounds deciding whether a
+// cofactor needs splitting.
+const primeRounds = 20
+
+var smallPrimes = sieve(trialLimit)
+
+func sieve(n int) []int64 {
+	composite := make([]bool, n)
+	var primes []int64
+	for i := 2; i < n; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, int64(i))
+		for j := i * i; j < n; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+func factor(ctx context.Context, n *big.Int) Factorization {
+	counts := make(map[string]*Factor)
+	add := func(p *big.Int) {
+		if f, ok := counts[p.String()]; ok {
+			f.Exponent++
+			return
+		}
+		counts[p.String()] = &Factor{new(big.Int).Set(p), 1}
+	}
+
+	rest := new(big.Int).Set(n)
+	q, r := new(big.Int), new(big.Int)
+	for _, sp := range smallPrimes {
+		p := big.NewInt(sp)
+		for {
+			q.QuoRem(rest, p, r)
+			if r.Sign() != 0 {
+				break
+			}
+			add(p)
+			rest.Set(q)
+		}
+	}
+
+	var fz Factorization
+	stack := []*big.Int{rest}
+	for len(stack) > 0 {
+		m := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch {
+		case m.Cmp(big.NewInt(1)) == 0:
+		case m.ProbablyPrime(primeRounds):
+			add(m)
+		default:
+			d := pollardRho(ctx, m)
+			if d == nil {
+				fz.Composite = append(fz.Composite, m)
+				continue
+			}
+			stack = append(stack, d, new(big.Int).Quo(m, d))
+		}
+	}
+
+	for _, f := range counts {
+		fz.Factors = append(fz.Factors, *f)
+	}
+	sort.Slice(fz.Factors, func(i, j int) bool {
+		return fz.Factors[i].Prime.Cmp(fz.Factors[j].Prime) < 0
+	})
+	sort.Slice(fz.Composite, func(i, j int) bool {
+		return fz.Composite[i].Cmp(fz.Composite[j]) < 0
+	})
+	fz.Complete = len(fz.Composite) == 0
+	return fz
+}
+
+// rhoBatch is the number of steps whose differences are multiplied together
+// before taking a gcd with n, and between checks for cancellation.
+const rhoBatch = 128
+
+// pollardRho returns a non-trivial factor of the composite n using Brent's
+// variant of Pollard's rho, trying successive polynomials x² + c until ctx
+// is done, when it returns nil.
+func pollardRho(ctx context.Context, n *big.Int) *big.Int {
+	one := big.NewInt(1)
+	step := func(x, c *big.Int) {
+		x.Mul(x, x).Add(x, c).Mod(x, n)
+	}
+	for c := int64(1); ; c++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		cb := big.NewInt(c)
+		y, x, ys := big.NewInt(2), new(big.Int), new(big.Int)
+		g, q, diff := big.NewInt(1), big.NewInt(1), new(big.Int)
+		for r := 1; g.Cmp(one) == 0; r *= 2 {
+			x.Set(y)
+			for i := 0; i < r; i++ {
+				if i%rhoBatch == 0 && ctx.Err() != nil {
+					return nil
+				}
+				step(y, cb)
+			}
+			for k := 0; k < r && g.Cmp(one) == 0; k += rhoBatch {
+				if ctx.Err() != nil {
+					return nil
+				}
+				ys.Set(y)
+				for i := 0; i < rhoBatch && i < r-k; i++ {
+					step(y, cb)
+					q.Mul(q, diff.Sub(x, y).Abs(diff)).Mod(q, n)
+				}
+				g.GCD(nil, nil, q, n)
+			}
+		}
+		if g.Cmp(n) == 0 {
+			// the batch overshot; redo it one step at a time
+			for {
+				step(ys, cb)
+				g.GCD(nil, nil, diff.Sub(x, ys).Abs(diff), n)
+				if g.Cmp(one) != 0 {
+					break
+				}
+			}
+		}
+		if g.Cmp(n) != 0 {
+			return g
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package numtheory
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// rsa100 is a 330-bit semiprime far beyond what rho can split in a test.
+const rsa100 = "1522605027922533360535618378132637429718068114961380688657908494580122963258952897654000350692006139"
+
+func mustInt(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("bad integer " + s)
+	}
+	return v
+}
+
+// product multiplies a factorization back together.
+func product(fz Factorization) *big.Int {
+	p := big.NewInt(1)
+	for _, f := range fz.Factors {
+		p.Mul(p, new(big.Int).Exp(f.Prime, big.NewInt(int64(f.Exponent)), nil))
+	}
+	for _, c := range fz.Composite {
+		p.Mul(p, c)
+	}
+	return p
+}
+
+func TestFactor(t *testing.T) {
+	tests := []struct {
+		n    string
+		want map[string]int
+	}{
+		{"1", map[string]int{}},
+		{"97", map[string]int{"97": 1}},
+		{"360", map[string]int{"2": 3, "3": 2, "5": 1}},
+		// semiprimes past trial division
+		{"1000000016000000063", map[string]int{"1000000007": 1, "1000000009": 1}},
+		{"18446743979220271189", map[string]int{"4294967279": 1, "4294967291": 1}},
+		{"4611686014132420609", map[string]int{"2147483647": 2}},
+		// perfect powers
+		{"10028029413722401", map[string]int{"10007": 4}},
+		{"1000009000027000027", map[string]int{"1000003": 3}},
+		{"18446744073709551616", map[string]int{"2": 64}},
+	}
+	for _, tt := range tests {
+		n := mustInt(tt.n)
+		fz := factor(context.Background(), n)
+		if !fz.Complete {
+			t.Errorf("factor(%s) incomplete: %v", tt.n, fz.Composite)
+			continue
+		}
+		if product(fz).Cmp(n) != 0 {
+			t.Errorf("factor(%s) multiplies back to %s", tt.n, product(fz))
+		}
+		for _, f := range fz.Factors {
+			if !f.Prime.ProbablyPrime(primeRounds) {
+				t.Errorf("factor(%s) has composite factor %s", tt.n, f.Prime)
+			}
+		}
+		got := make(map[string]int)
+		for _, f := range fz.Factors {
+			got[f.Prime.String()] = f.Exponent
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("factor(%s) = %v, want %v", tt.n, got, tt.want)
+			continue
+		}
+		for p, k := range tt.want {
+			if got[p] != k {
+				t.Errorf("factor(%s) = %v, want %v", tt.n, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestFactorBudget(t *testing.T) {
+	svc := NewService(Config{MaxBudget: time.Second})
+	n := mustInt(rsa100)
+
+	for _, budget := range []time.Duration{-time.Second, 2 * time.Second} {
+		if _, err := svc.Factor(context.Background(), n, budget); !errors.Is(err, ErrBudget) {
+			t.Errorf("budget %v: got %v, want %v", budget, err, ErrBudget)
+		}
+	}
+
+	// running out of budget returns what was found so far
+	begin := time.Now()
+	fz, err := svc.Factor(context.Background(), n, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(begin); took > 500*time.Millisecond {
+		t.Errorf("50ms budget took %v", took)
+	}
+	if fz.Complete || len(fz.Composite) != 1 || fz.Composite[0].Cmp(n) != 0 {
+		t.Errorf("got %+v, want n left as an incomplete composite", fz)
+	}
+
+	// no budget means the default, cut to MaxBudget when that is shorter
+	svc = NewService(Config{MaxBudget: 30 * time.Millisecond})
+	begin = time.Now()
+	if _, err := svc.Factor(context.Background(), n, 0); err != nil {
+		t.Fatal(err)
+	}
+	if took := time.Since(begin); took > 500*time.Millisecond {
+		t.Errorf("default budget took %v with a 30ms maximum", took)
+	}
+}
+
+func TestPollardRhoCancelled(t *testing.T) {
+	n := mustInt(rsa100)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if d := pollardRho(ctx, n); d != nil {
+		t.Errorf("cancelled pollardRho returned %s", d)
+	}
+
+	// cancellation is noticed part way through a cycle, not only between
+	// polynomials
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	begin := time.Now()
+	if d := pollardRho(ctx, n); d != nil {
+		t.Errorf("pollardRho returned %s", d)
+	}
+	if took := time.Since(begin); took > 500*time.Millisecond {
+		t.Errorf("pollardRho took %v to stop", took)
+	}
+}
@@ -0,0 +1,173 @@
+package numtheory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"go.uber.org/zap"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+func LoggingMiddleware(logger zap.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+func NewInstrumentingMiddleware(
+	counter metrics.Counter,
+	latency metrics.Histogram,
+	s Service,
+) Service {
+	return &instrumentingMiddleware{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger zap.Logger
+}
+
+type instrumentingMiddleware struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+func (mw loggingMiddleware) IsPrime(n *big.Int, rounds int) (prime bool, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "IsPrime"),
+			zap.Int("bits", n.BitLen()),
+			zap.Int("rounds", rounds),
+			zap.Bool("output", prime),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	prime, err = mw.next.IsPrime(n, rounds)
+	return
+}
+
+func (mw loggingMiddleware) Factor(ctx context.Context, n *big.Int, budget time.Duration) (fz Factorization, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Factor"),
+			zap.Int("bits", n.BitLen()),
+			zap.Duration("budget", budget),
+			zap.Int("factors", len(fz.Factors)),
+			zap.Bool("complete", fz.Complete),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	fz, err = mw.next.Factor(ctx, n, budget)
+	return
+}
+
+func (mw loggingMiddleware) GCD(a, b *big.Int) (g GCD, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "GCD"),
+			zap.Int("bits_a", a.BitLen()),
+			zap.Int("bits_b", b.BitLen()),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	g, err = mw.next.GCD(a, b)
+	return
+}
+
+func (mw loggingMiddleware) ModPow(base, exp, mod *big.Int) (v *big.Int, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "ModPow"),
+			zap.Int("bits_exp", exp.BitLen()),
+			zap.Int("bits_mod", mod.BitLen()),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	v, err = mw.next.ModPow(base, exp, mod)
+	return
+}
+
+func (mw loggingMiddleware) ModInverse(a, mod *big.Int) (v *big.Int, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "ModInverse"),
+			zap.Int("bits_mod", mod.BitLen()),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	v, err = mw.next.ModInverse(a, mod)
+	return
+}
+
+func (mw instrumentingMiddleware) IsPrime(n *big.Int, rounds int) (prime bool, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "isprime", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	prime, err = mw.next.IsPrime(n, rounds)
+	return
+}
+
+func (mw instrumentingMiddleware) Factor(ctx context.Context, n *big.Int, budget time.Duration) (fz Factorization, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "factor", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	fz, err = mw.next.Factor(ctx, n, budget)
+	return
+}
+
+func (mw instrumentingMiddleware) GCD(a, b *big.Int) (g GCD, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "gcd", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	g, err = mw.next.GCD(a, b)
+	return
+}
+
+func (mw instrumentingMiddleware) ModPow(base, exp, mod *big.Int) (v *big.Int, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "modpow", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	v, err = mw.next.ModPow(base, exp, mod)
+	return
+}
+
+func (mw instrumentingMiddleware) ModInverse(a, mod *big.Int) (v *big.Int, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "modinverse", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	v, err = mw.next.ModInverse(a, mod)
+	return
+}
@@ -0,0 +1,162 @@
+package numtheory
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+)
+
+type Service interface {
+	IsPrime(n *big.Int, rounds int) (bool, error)
+	Factor(ctx context.Context, n *big.Int, budget time.Duration) (Factorization, error)
+	GCD(a, b *big.Int) (GCD, error)
+	ModPow(base, exp, mod *big.Int) (*big.Int, error)
+	ModInverse(a, mod *big.Int) (*big.Int, error)
+}
+
+// GCD holds the greatest common divisor and least common multiple of a and
+// b, with Bézout coefficients X and Y such that aX + bY = GCD.
+type GCD struct {
+	GCD *big.Int
+	LCM *big.Int
+	X   *big.Int
+	Y   *big.Int
+}
+
+const (
+	// maxBits bounds every input, keeping a modular exponentiation, and so
+	// each Miller-Rabin round, well under a second.
+	maxBits = 4096
+
+	maxRounds = 64
+)
+
+var (
+	ErrTooLarge  = errors.New("Number too large")
+	ErrRounds    = errors.New("Rounds out of range")
+	ErrNegative  = errors.New("Number must be positive")
+	ErrModulus   = errors.New("Modulus must be positive")
+	ErrNoInverse = errors.New("No modular inverse exists")
+	ErrBudget    = errors.New("Time budget out of range")
+)
+
+// defaultBudget is the time Factor is given when no budget is asked for,
+// unless MaxBudget is shorter.
+const defaultBudget = 2 * time.Second
+
+// Config holds the limits of a math service.
+type Config struct {
+	// MaxBudget is the longest time Factor may be given.
+	MaxBudget time.Duration
+}
+
+type service struct {
+	maxBudget time.Duration
+}
+
+func checkSize(ns ...*big.Int) error {
+	for _, n := range ns {
+		if n.BitLen() > maxBits {
+			return ErrTooLarge
+		}
+	}
+	return nil
+}
+
+// IsPrime runs rounds of Miller-Rabin with random bases, plus the
+// Baillie-PSW test, on n. Numbers below 2^64 are tested deterministically.
+func (service) IsPrime(n *big.Int, rounds int) (bool, error) {
+	if rounds < 0 || rounds > maxRounds {
+		return false, ErrRounds
+	}
+	if err := checkSize(n); err != nil {
+		return false, err
+	}
+	return n.ProbablyPrime(rounds), nil
+}
+
+// Factor factors n > 0 into probable primes by trial division and Pollard's
+// rho, giving up on the factors it has not split once budget runs out. A
+// zero budget means the default.
+func (svc *service) Factor(ctx context.Context, n *big.Int, budget time.Duration) (Factorization, error) {
+	if n.Sign() <= 0 {
+		return Factorization{}, ErrNegative
+	}
+	if err := checkSize(n); err != nil {
+		return Factorization{}, err
+	}
+	if budget == 0 {
+		budget = defaultBudget
+		if budget > svc.maxBudget {
+			budget = svc.maxBudget
+		}
+	}
+	if budget <= 0 || budget > svc.maxBudget {
+		return Factorization{}, ErrBudget
+	}
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	return factor(ctx, n), nil
+}
+
+func (service) GCD(a, b *big.Int) (GCD, error) {
+	if err := checkSize(a, b); err != nil {
+		return GCD{}, err
+	}
+	g := GCD{new(big.Int), new(big.Int), new(big.Int), new(big.Int)}
+	// big.Int.GCD wants non-negative operands; fix the signs up after
+	g.GCD.GCD(g.X, g.Y, new(big.Int).Abs(a), new(big.Int).Abs(b))
+	if a.Sign() < 0 {
+		g.X.Neg(g.X)
+	}
+	if b.Sign() < 0 {
+		g.Y.Neg(g.Y)
+	}
+	if g.GCD.Sign() != 0 {
+		g.LCM.Mul(a, b).Abs(g.LCM).Quo(g.LCM, g.GCD)
+	}
+	return g, nil
+}
+
+// ModPow returns base^exp mod mod, in [0, mod). A negative exponent raises
+// the inverse of base.
+func (service) ModPow(base, exp, mod *big.Int) (*big.Int, error) {
+	if mod.Sign() <= 0 {
+		return nil, ErrModulus
+	}
+	if err := checkSize(base, exp, mod); err != nil {
+		return nil, err
+	}
+	b := new(big.Int).Mod(base, mod)
+	e := exp
+	if exp.Sign() < 0 {
+		if b.ModInverse(b, mod) == nil {
+			return nil, ErrNoInverse
+		}
+		e = new(big.Int).Neg(exp)
+	}
+	return b.Exp(b, e, mod), nil
+}
+
+func (service) ModInverse(a, mod *big.Int) (*big.Int, error) {
+	if mod.Sign() <= 0 {
+		return nil, ErrModulus
+	}
+	if err := checkSize(a, mod); err != nil {
+		return nil, err
+	}
+	if mod.Cmp(big.NewInt(1)) == 0 {
+		return new(big.Int), nil
+	}
+	inv := new(big.Int).Mod(a, mod)
+	if inv.ModInverse(inv, mod) == nil {
+		return nil, ErrNoInverse
+	}
+	return inv, nil
+}
+
+// NewService returns a math service limited by cfg.
+func NewService(cfg Config) Service {
+	return &service{maxBudget: cfg.MaxBudget}
+}
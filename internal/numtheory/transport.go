@@ -0,0 +1,126 @@
+package numtheory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+var BadNumber = errors.New("Bad number in request")
+
+func MakeHandler(ms Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	primeHandler := kithttp.NewServer(
+		makePrimeEndpoint(ms),
+		decodePrimeRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	factorHandler := kithttp.NewServer(
+		makeFactorEndpoint(ms),
+		decodeFactorRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	gcdHandler := kithttp.NewServer(
+		makeGCDEndpoint(ms),
+		decodeGCDRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	modPowHandler := kithttp.NewServer(
+		makeModPowEndpoint(ms),
+		decodeModPowRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	modInverseHandler := kithttp.NewServer(
+		makeModInverseEndpoint(ms),
+		decodeModInverseRequest,
+		encodeResponse,
+		opts...,
+	)
+
+	r := mux.NewRouter()
+
+	r.Path("/math/prime").Handler(primeHandler).Methods("POST")
+	r.Path("/math/factor").Handler(factorHandler).Methods("POST")
+	r.Path("/math/gcd").Handler(gcdHandler).Methods("POST")
+	r.Path("/math/modpow").Handler(modPowHandler).Methods("POST")
+	r.Path("/math/modinverse").Handler(modInverseHandler).Methods("POST")
+
+	return r
+}
+
+func decodePrimeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request primeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadNumber
+	}
+	return request, nil
+}
+
+func decodeFactorRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request factorRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadNumber
+	}
+	return request, nil
+}
+
+func decodeGCDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request gcdRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadNumber
+	}
+	return request, nil
+}
+
+func decodeModPowRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request modPowRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadNumber
+	}
+	return request, nil
+}
+
+func decodeModInverseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request modInverseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, BadNumber
+	}
+	return request, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeError writes err as JSON with a status code matching its cause.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	switch {
+	case errors.Is(err, ErrTooLarge):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Is(err, BadNumber), errors.Is(err, ErrRounds),
+		errors.Is(err, ErrNegative), errors.Is(err, ErrModulus),
+		errors.Is(err, ErrNoInverse), errors.Is(err, ErrBudget):
+		w.WriteHeader(http.StatusBadRequest)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"err": err.Error(),
+	})
+}
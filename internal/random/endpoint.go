@@ -0,0 +1,57 @@
+package random
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type stringRequest struct {
+	Length   int      `json:"length"`
+	Alphabet string   `json:"alphabet,omitempty"`
+	Charset  string   `json:"charset,omitempty"`
+	Require  []string `json:"require,omitempty"`
+}
+
+type passphraseRequest struct {
+	Words     int      `json:"words"`
+	Wordlist  string   `json:"wordlist,omitempty"`
+	Custom    []string `json:"custom,omitempty"`
+	Separator *string  `json:"separator,omitempty"`
+}
+
+type secretResponse struct {
+	Value   string  `json:"value,omitempty"`
+	Entropy float64 `json:"entropy,omitempty"`
+	Err     string  `json:"err,omitempty"`
+}
+
+func makeStringEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(stringRequest)
+		s, err := svc.String(req.Length, req.Alphabet, req.Charset, req.Require)
+		if err != nil {
+			return secretResponse{Err: err.Error()}, nil
+		}
+		return secretResponse{s.Value, s.Entropy, ""}, nil
+	}
+}
+
+func makePassphraseEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(passphraseRequest)
+		words := req.Words
+		if words == 0 {
+			words = 6
+		}
+		separator := " "
+		if req.Separator != nil {
+			separator = *req.Separator
+		}
+		s, err := svc.Passphrase(words, req.Wordlist, req.Custom, separator)
+		if err != nil {
+			return secretResponse{Err: err.Error()}, nil
+		}
+		return secretResponse{s.Value, s.Entropy, ""}, nil
+	}
+}
@@ -0,0 +1,100 @@
+package random
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"go.uber.org/zap"
+)
+
+// Middleware describes a service (as opposed to endpoint) middleware.
+type Middleware func(Service) Service
+
+// LoggingMiddleware never logs the generated values themselves.
+func LoggingMiddleware(logger zap.Logger) Middleware {
+	return func(next Service) Service {
+		return &loggingMiddleware{
+			next:   next,
+			logger: logger,
+		}
+	}
+}
+
+func NewInstrumentingMiddleware(
+	counter metrics.Counter,
+	latency metrics.Histogram,
+	s Service,
+) Service {
+	return &instrumentingMiddleware{
+		requestCount:   counter,
+		requestLatency: latency,
+		next:           s,
+	}
+}
+
+type loggingMiddleware struct {
+	next   Service
+	logger zap.Logger
+}
+
+type instrumentingMiddleware struct {
+	requestCount   metrics.Counter
+	requestLatency metrics.Histogram
+	next           Service
+}
+
+func (mw loggingMiddleware) String(length int, alphabet, charset string, require []string) (s Secret, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "String"),
+			zap.Int("length", length),
+			zap.String("alphabet", alphabet),
+			zap.Int("charset", len(charset)),
+			zap.Strings("require", require),
+			zap.Float64("entropy", s.Entropy),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	s, err = mw.next.String(length, alphabet, charset, require)
+	return
+}
+
+func (mw loggingMiddleware) Passphrase(words int, wordlist string, custom []string, separator string) (s Secret, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Passphrase"),
+			zap.Int("words", words),
+			zap.String("wordlist", wordlist),
+			zap.Int("custom", len(custom)),
+			zap.Float64("entropy", s.Entropy),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	s, err = mw.next.Passphrase(words, wordlist, custom, separator)
+	return
+}
+
+func (mw instrumentingMiddleware) String(length int, alphabet, charset string, require []string) (s Secret, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "string", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	s, err = mw.next.String(length, alphabet, charset, require)
+	return
+}
+
+func (mw instrumentingMiddleware) Passphrase(words int, wordlist string, custom []string, separator string) (s Secret, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "passphrase", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	s, err = mw.next.Passphrase(words, wordlist, custom, separator)
+	return
+}
@@ -0,0 +1,301 @@
+package random
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+type Service interface {
+	String(length int, alphabet, charset string, require []string) (Secret, error)
+	Passphrase(words int, wordlist string, custom []string, separator string) (Secret, error)
+}
+
+// Secret is a generated value with the entropy, in bits, of the process
+// that generated it.
+type Secret struct {
+	Value   string  `json:"value"`
+	Entropy float64 `json:"entropy"`
+}
+
+const (
+	maxLength = 1024
+	maxWords  = 64
+)
+
+var (
+	ErrLength   = errors.New("Length out of range")
+	ErrAlphabet = errors.New("Unknown alphabet")
+	ErrCharset  = errors.New("Charset needs at least two distinct characters")
+	ErrClass    = errors.New("Unknown character class")
+	ErrRequire  = errors.New("Required character classes cannot be satisfied")
+	ErrWords    = errors.New("Word count out of range")
+	ErrWordlist = errors.New("Unknown word list")
+)
+
+const (
+	lower  = "abcdefghijklmnopqrstuvwxyz"
+	upper  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digits = "0123456789"
+	// symbols is every printable ASCII character that is not alphanumeric.
+	symbols = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+)
+
+var alphabets = map[string]string{
+	"lower":     lower,
+	"upper":     upper,
+	"digits":    digits,
+	"alpha":     lower + upper,
+	"alnum":     lower + upper + digits,
+	"hex":       digits + "abcdef",
+	"symbols":   symbols,
+	"printable": lower + upper + digits + symbols,
+}
+
+var classes = map[string]func(rune) bool{
+	"lower":  unicode.IsLower,
+	"upper":  unicode.IsUpper,
+	"digit":  unicode.IsDigit,
+	"symbol": func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) },
+}
+
+type service struct{}
+
+// String draws length characters from charset, or from the named alphabet
+// when charset is empty, such that every required character class appears.
+// The result is uniform over all strings that satisfy the requirements, and
+// Entropy is exact for that distribution.
+func (service) String(length int, alphabet, charset string, require []string) (Secret, error) {
+	if length <= 0 || length > maxLength {
+		return Secret{}, ErrLength
+	}
+	chars, err := resolveCharset(alphabet, charset)
+	if err != nil {
+		return Secret{}, err
+	}
+	var required []func(rune) bool
+	seen := make(map[string]bool)
+	for _, name := range require {
+		name = strings.ToLower(name)
+		is, ok := classes[name]
+		if !ok {
+			return Secret{}, ErrClass
+		}
+		if !seen[name] {
+			seen[name] = true
+			required = append(required, is)
+		}
+	}
+
+	groups := groupByClass(chars, required)
+	all := 1<<len(required) - 1
+	count := satisfying(groups, all, length)
+	if count.Sign() == 0 {
+		return Secret{}, ErrRequire
+	}
+	buf, err := sample(groups, all, length)
+	if err != nil {
+		return Secret{}, err
+	}
+	return Secret{string(buf), log2(count)}, nil
+}
+
+// Passphrase joins words picked uniformly from the named word list, or from
+// custom when one is given.
+func (service) Passphrase(words int, wordlist string, custom []string, separator string) (Secret, error) {
+	if words <= 0 || words > maxWords {
+		return Secret{}, ErrWords
+	}
+	list := dedupe(custom)
+	if len(custom) == 0 {
+		if wordlist == "" {
+			wordlist = "eff_large"
+		}
+		var ok bool
+		if list, ok = wordlists[strings.ToLower(wordlist)]; !ok {
+			return Secret{}, ErrWordlist
+		}
+	} else if len(list) < 2 {
+		return Secret{}, ErrWordlist
+	}
+
+	picked := make([]string, words)
+	for i := range picked {
+		n, err := randInt(len(list))
+		if err != nil {
+			return Secret{}, err
+		}
+		picked[i] = list[n]
+	}
+	return Secret{
+		Value:   strings.Join(picked, separator),
+		Entropy: float64(words) * math.Log2(float64(len(list))),
+	}, nil
+}
+
+func resolveCharset(alphabet, charset string) ([]rune, error) {
+	if charset == "" {
+		if alphabet == "" {
+			alphabet = "alnum"
+		}
+		var ok bool
+		if charset, ok = alphabets[strings.ToLower(alphabet)]; !ok {
+			return nil, ErrAlphabet
+		}
+	}
+	seen := make(map[rune]bool)
+	var chars []rune
+	for _, r := range charset {
+		if !seen[r] {
+			seen[r] = true
+			chars = append(chars, r)
+		}
+	}
+	if len(chars) < 2 {
+		return nil, ErrCharset
+	}
+	return chars, nil
+}
+
+func dedupe(words []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" && !seen[w] {
+			seen[w] = true
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// classGroup is the characters that belong to exactly the required classes
+// in mask.
+type classGroup struct {
+	mask  int
+	chars []rune
+}
+
+func groupByClass(chars []rune, required []func(rune) bool) []classGroup {
+	var groups []classGroup
+	index := make(map[int]int)
+	for _, r := range chars {
+		mask := 0
+		for i, is := range required {
+			if is(r) {
+				mask |= 1 << i
+			}
+		}
+		if _, ok := index[mask]; !ok {
+			index[mask] = len(groups)
+			groups = append(groups, classGroup{mask: mask})
+		}
+		g := &groups[index[mask]]
+		g.chars = append(g.chars, r)
+	}
+	return groups
+}
+
+// satisfying counts the strings of the given length over the grouped
+// characters that contain every class in missing, by inclusion-exclusion
+// over the classes left out.
+func satisfying(groups []classGroup, missing, length int) *big.Int {
+	total := new(big.Int)
+	for out := missing; ; out = (out - 1) & missing {
+		allowed := 0
+		for _, g := range groups {
+			if g.mask&out == 0 {
+				allowed += len(g.chars)
+			}
+		}
+		term := new(big.Int).Exp(big.NewInt(int64(allowed)), big.NewInt(int64(length)), nil)
+		if popcount(out)%2 == 1 {
+			total.Sub(total, term)
+		} else {
+			total.Add(total, term)
+		}
+		if out == 0 {
+			return total
+		}
+	}
+}
+
+// sample draws a string uniformly from those counted by satisfying. Each
+// character is picked with probability proportional to the number of ways
+// the rest of the string can still supply the missing classes, so no draw
+// is ever rejected.
+func sample(groups []classGroup, missing, length int) ([]rune, error) {
+	var all []rune
+	for _, g := range groups {
+		all = append(all, g.chars...)
+	}
+	buf := make([]rune, length)
+	weights := make([]*big.Int, len(groups))
+	for i := range buf {
+		if missing == 0 {
+			n, err := randInt(len(all))
+			if err != nil {
+				return nil, err
+			}
+			buf[i] = all[n]
+			continue
+		}
+
+		total := new(big.Int)
+		for j, g := range groups {
+			weights[j] = satisfying(groups, missing&^g.mask, length-i-1)
+			weights[j].Mul(weights[j], big.NewInt(int64(len(g.chars))))
+			total.Add(total, weights[j])
+		}
+		v, err := rand.Int(rand.Reader, total)
+		if err != nil {
+			return nil, err
+		}
+		for j, g := range groups {
+			if v.Cmp(weights[j]) < 0 {
+				n, err := randInt(len(g.chars))
+				if err != nil {
+					return nil, err
+				}
+				buf[i] = g.chars[n]
+				missing &^= g.mask
+				break
+			}
+			v.Sub(v, weights[j])
+		}
+	}
+	return buf, nil
+}
+
+func popcount(n int) (c int) {
+	for ; n > 0; n &= n - 1 {
+		c++
+	}
+	return c
+}
+
+// log2 of a positive big integer, to float64 precision.
+func log2(x *big.Int) float64 {
+	shift := x.BitLen() - 53
+	if shift <= 0 {
+		f, _ := new(big.Float).SetInt(x).Float64()
+		return math.Log2(f)
+	}
+	f, _ := new(big.Float).SetInt(new(big.Int).Rsh(x, uint(shift))).Float64()
+	return math.Log2(f) + float64(shift)
+}
+
+func randInt(n int) (int, error) {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(v.Int64()), nil
+}
+
+func NewService() Service {
+	return &service{}
+}
@@ -0,0 +1,53 @@
+package random
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+)
+
+func MakeHandler(rs Service) http.Handler {
+	stringHandler := kithttp.NewServer(
+		makeStringEndpoint(rs),
+		decodeStringRequest,
+		encodeResponse,
+	)
+
+	passphraseHandler := kithttp.NewServer(
+		makePassphraseEndpoint(rs),
+		decodePassphraseRequest,
+		encodeResponse,
+	)
+
+	r := mux.NewRouter()
+
+	r.Path("/random/string").Handler(stringHandler).Methods("POST")
+	r.Path("/random/passphrase").Handler(passphraseHandler).Methods("POST")
+
+	return r
+}
+
+func decodeStringRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request stringRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodePassphraseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request passphraseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// encodeResponse marks responses as uncacheable, since they carry secrets.
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Cache-Control", "no-store")
+	return json.NewEncoder(w).Encode(response)
+}
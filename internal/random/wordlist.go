@@ -0,0 +1,20 @@
+package random
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// The EFF diceware word lists, see
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
+var (
+	//go:embed wordlists/eff_large.txt
+	effLarge string
+	//go:embed wordlists/eff_short.txt
+	effShort string
+)
+
+var wordlists = map[string][]string{
+	"eff_large": strings.Fields(effLarge),
+	"eff_short": strings.Fields(effShort),
+}
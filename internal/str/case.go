@@ -0,0 +1,131 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// identifierWords splits s into words at non-alphanumeric runes and at case
+// changes, keeping acronyms together: "parseHTTPRequest2" becomes
+// "parse", "HTTP", "Request2".
+func identifierWords(s string) []string {
+	var words []string
+	rs := []rune(s)
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		if unicode.IsUpper(r) {
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(rs[start:i]))
+				start = i
+			}
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+func capitalize(w string) string {
+	rs := []rune(strings.ToLower(w))
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
+
+func convertCase(s, style string) (string, error) {
+	words := identifierWords(s)
+	switch strings.ToLower(style) {
+	case "camel":
+		for i, w := range words {
+			if i == 0 {
+				words[i] = strings.ToLower(w)
+			} else {
+				words[i] = capitalize(w)
+			}
+		}
+		return strings.Join(words, ""), nil
+	case "pascal":
+		for i, w := range words {
+			words[i] = capitalize(w)
+		}
+		return strings.Join(words, ""), nil
+	case "snake":
+		return strings.ToLower(strings.Join(words, "_")), nil
+	case "screaming_snake":
+		return strings.ToUpper(strings.Join(words, "_")), nil
+	case "kebab":
+		return strings.ToLower(strings.Join(words, "-")), nil
+	case "title":
+		for i, w := range words {
+			words[i] = capitalize(w)
+		}
+		return strings.Join(words, " "), nil
+	default:
+		return "", ErrStyle
+	}
+}
+
+// asciiFold replaces letters that have no decomposition into an ASCII base
+// letter plus marks.
+var asciiFold = strings.NewReplacer(
+	"ß", "ss", "ẞ", "SS",
+	"æ", "ae", "Æ", "AE",
+	"œ", "oe", "Œ", "OE",
+	"ø", "o", "Ø", "O",
+	"ł", "l", "Ł", "L",
+	"đ", "d", "Đ", "D",
+	"ð", "d", "Ð", "D",
+	"þ", "th", "Þ", "TH",
+	"ı", "i",
+)
+
+func slugify(s, separator string, maxLen int) string {
+	s, _, _ = transform.String(
+		transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC),
+		asciiFold.Replace(s),
+	)
+
+	var buf strings.Builder
+	pending := false
+	for _, r := range strings.ToLower(s) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pending && buf.Len() > 0 {
+				buf.WriteString(separator)
+			}
+			pending = false
+			buf.WriteRune(r)
+			continue
+		}
+		pending = true
+	}
+
+	slug := buf.String()
+	if maxLen > 0 && len(slug) > maxLen {
+		cut := slug[:maxLen]
+		// prefer cutting at a separator over leaving half a word
+		if !strings.HasPrefix(slug[maxLen:], separator) {
+			if i := strings.LastIndex(cut, separator); i > 0 {
+				cut = cut[:i]
+			}
+		}
+		slug = strings.TrimSuffix(cut, separator)
+	}
+	return slug
+}
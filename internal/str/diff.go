@@ -0,0 +1,225 @@
+package str
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// maxDiffEdits bounds the edit distance the Myers search will explore. The
+// trace it keeps for backtracking grows quadratically with the distance.
+const maxDiffEdits = 2000
+
+const (
+	opEqual  = "equal"
+	opDelete = "delete"
+	opInsert = "insert"
+)
+
+// DiffOp is a single line or word of a hunk.
+type DiffOp struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// Hunk is a run of changes with its surrounding context. Starts are 1-based
+// as in unified diff headers.
+type Hunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Ops      []DiffOp `json:"ops"`
+}
+
+type edit struct {
+	kind string
+	a, b int
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// splitWords splits s on Unicode word boundaries, dropping white space.
+func splitWords(s string) []string {
+	var words []string
+	state := -1
+	var word string
+	for len(s) > 0 {
+		word, s, state = uniseg.FirstWordInString(s, state)
+		if strings.TrimFunc(word, unicode.IsSpace) != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// myers returns the shortest edit script turning a into b.
+func myers(a, b []string) ([]edit, error) {
+	n, m := len(a), len(b)
+	max := n + m
+	if max > maxDiffEdits {
+		max = maxDiffEdits
+	}
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	found := false
+	var d int
+	for d = 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+	if !found {
+		return nil, ErrDiffTooLarge
+	}
+
+	var edits []edit
+	x, y := n, m
+	for ; d > 0; d-- {
+		prev := trace[d]
+		k := x - y
+		var pk int
+		if k == -d || (k != d && prev[k-1+d] < prev[k+1+d]) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := prev[pk+d]
+		py := px - pk
+		for x > px && y > py {
+			x--
+			y--
+			edits = append(edits, edit{opEqual, x, y})
+		}
+		if x == px {
+			y--
+			edits = append(edits, edit{opInsert, x, y})
+		} else {
+			x--
+			edits = append(edits, edit{opDelete, x, y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		edits = append(edits, edit{opEqual, x, y})
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits, nil
+}
+
+// hunks groups an edit script into hunks with up to context unchanged
+// tokens on either side of each change.
+func hunks(edits []edit, a, b []string, context int) []Hunk {
+	var out []Hunk
+	for i := 0; i < len(edits); {
+		if edits[i].kind == opEqual {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(edits) {
+			if edits[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].kind == opEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end += context
+				if end > run {
+					end = run
+				}
+				break
+			}
+			end = run
+		}
+
+		h := Hunk{OldStart: edits[start].a + 1, NewStart: edits[start].b + 1}
+		for _, e := range edits[start:end] {
+			switch e.kind {
+			case opEqual:
+				h.Ops = append(h.Ops, DiffOp{opEqual, a[e.a]})
+				h.OldLines++
+				h.NewLines++
+			case opDelete:
+				h.Ops = append(h.Ops, DiffOp{opDelete, a[e.a]})
+				h.OldLines++
+			case opInsert:
+				h.Ops = append(h.Ops, DiffOp{opInsert, b[e.b]})
+				h.NewLines++
+			}
+		}
+		if h.OldLines == 0 {
+			h.OldStart--
+		}
+		if h.NewLines == 0 {
+			h.NewStart--
+		}
+		out = append(out, h)
+		i = end
+	}
+	return out
+}
+
+// Unified renders hunks in unified diff format.
+func Unified(hs []Hunk) string {
+	if len(hs) == 0 {
+		return ""
+	}
+	var buf strings.Builder
+	buf.WriteString("--- a\n+++ b\n")
+	for _, h := range hs {
+		fmt.Fprintf(&buf, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+		for _, op := range h.Ops {
+			switch op.Kind {
+			case opEqual:
+				buf.WriteByte(' ')
+			case opDelete:
+				buf.WriteByte('-')
+			case opInsert:
+				buf.WriteByte('+')
+			}
+			buf.WriteString(op.Text)
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,138 @@
+package str
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// apply runs an edit script over a, checking that equal edits really are
+// equal and that every token of a and b is visited in order.
+func apply(t *testing.T, edits []edit, a, b []string) []string {
+	t.Helper()
+	var out []string
+	ai, bi := 0, 0
+	for _, e := range edits {
+		switch e.kind {
+		case opEqual:
+			if e.a != ai || e.b != bi || a[e.a] != b[e.b] {
+				t.Fatalf("bad equal edit %+v at a=%d b=%d", e, ai, bi)
+			}
+			out = append(out, a[e.a])
+			ai++
+			bi++
+		case opDelete:
+			if e.a != ai {
+				t.Fatalf("bad delete edit %+v at a=%d", e, ai)
+			}
+			ai++
+		case opInsert:
+			if e.b != bi {
+				t.Fatalf("bad insert edit %+v at b=%d", e, bi)
+			}
+			out = append(out, b[e.b])
+			bi++
+		}
+	}
+	if ai != len(a) || bi != len(b) {
+		t.Fatalf("script stops at a=%d b=%d of %d, %d", ai, bi, len(a), len(b))
+	}
+	return out
+}
+
+// changes counts the inserts and deletes of an edit script.
+func changes(edits []edit) int {
+	n := 0
+	for _, e := range edits {
+		if e.kind != opEqual {
+			n++
+		}
+	}
+	return n
+}
+
+// lcsDistance is the insert/delete distance between a and b, by dynamic
+// programming over their longest common subsequence.
+func lcsDistance(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else if dp[i+1][j] > dp[i][j+1] {
+				dp[i][j] = dp[i+1][j]
+			} else {
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+	return len(a) + len(b) - 2*dp[0][0]
+}
+
+func TestMyers(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		changes int
+	}{
+		{"both empty", "", "", 0},
+		{"empty old", "", "a b c", 3},
+		{"empty new", "a b c", "", 3},
+		{"identical", "a b c d", "a b c d", 0},
+		{"pure insert", "a b d", "a b c d", 1},
+		{"pure delete", "a b c d", "a c d", 1},
+		{"replace", "a b c", "a x c", 2},
+		{"reordered", "a b c d e", "e d c b a", 8},
+	}
+	for _, tt := range tests {
+		a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+		edits, err := myers(a, b)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := apply(t, edits, a, b); strings.Join(got, " ") != tt.b {
+			t.Errorf("%s: applied script gives %v, want %v", tt.name, got, b)
+		}
+		if got := changes(edits); got != tt.changes {
+			t.Errorf("%s: %d changes, want %d", tt.name, got, tt.changes)
+		}
+	}
+}
+
+func TestMyersRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	words := func() []string {
+		w := make([]string, rng.Intn(30))
+		for i := range w {
+			w[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return w
+	}
+	for i := 0; i < 500; i++ {
+		a, b := words(), words()
+		edits, err := myers(a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := apply(t, edits, a, b); strings.Join(got, " ") != strings.Join(b, " ") {
+			t.Fatalf("%v -> %v: applied script gives %v", a, b, got)
+		}
+		if got, want := changes(edits), lcsDistance(a, b); got != want {
+			t.Errorf("%v -> %v: %d changes, want the minimum %d", a, b, got, want)
+		}
+	}
+}
+
+func TestMyersTooLarge(t *testing.T) {
+	a := make([]string, maxDiffEdits)
+	b := make([]string, maxDiffEdits)
+	for i := range a {
+		a[i], b[i] = "a", "b"
+	}
+	if _, err := myers(a, b); err != ErrDiffTooLarge {
+		t.Errorf("got %v, want %v", err, ErrDiffTooLarge)
+	}
+}
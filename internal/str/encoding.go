@@ -0,0 +1,134 @@
+package str
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"mime/quotedprintable"
+	"net/url"
+	"strings"
+)
+
+// DecodeError reports the byte offset of the first invalid input to Decode.
+type DecodeError struct {
+	Scheme string
+	Offset int
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Invalid %s input at byte %d", e.Scheme, e.Offset)
+}
+
+type codec struct {
+	encode func(string) string
+	decode func(string) ([]byte, error)
+}
+
+var codecs = map[string]codec{
+	"base64":        base64Codec("base64", base64.StdEncoding),
+	"base64-raw":    base64Codec("base64-raw", base64.RawStdEncoding),
+	"base64url":     base64Codec("base64url", base64.URLEncoding),
+	"base64url-raw": base64Codec("base64url-raw", base64.RawURLEncoding),
+	"base32":        base32Codec("base32", base32.StdEncoding),
+	"base32-raw":    base32Codec("base32-raw", base32.StdEncoding.WithPadding(base32.NoPadding)),
+	"hex": {
+		func(s string) string { return hex.EncodeToString([]byte(s)) },
+		decodeHex,
+	},
+	"url": {
+		url.QueryEscape,
+		decodeURL,
+	},
+	"html": {
+		html.EscapeString,
+		func(s string) ([]byte, error) { return []byte(html.UnescapeString(s)), nil },
+	},
+	"quoted-printable": {
+		encodeQuotedPrintable,
+		decodeQuotedPrintable,
+	},
+}
+
+func base64Codec(name string, enc *base64.Encoding) codec {
+	return codec{
+		func(s string) string { return enc.EncodeToString([]byte(s)) },
+		func(s string) ([]byte, error) {
+			b, err := enc.DecodeString(s)
+			if off, ok := err.(base64.CorruptInputError); ok {
+				return nil, &DecodeError{name, int(off)}
+			}
+			return b, err
+		},
+	}
+}
+
+func base32Codec(name string, enc *base32.Encoding) codec {
+	return codec{
+		func(s string) string { return enc.EncodeToString([]byte(s)) },
+		func(s string) ([]byte, error) {
+			b, err := enc.DecodeString(s)
+			if off, ok := err.(base32.CorruptInputError); ok {
+				return nil, &DecodeError{name, int(off)}
+			}
+			return b, err
+		},
+	}
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// decodeHex locates bad input itself since the hex package only reports
+// the offending byte.
+func decodeHex(s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		if !isHex(s[i]) {
+			return nil, &DecodeError{"hex", i}
+		}
+	}
+	if len(s)%2 == 1 {
+		return nil, &DecodeError{"hex", len(s)}
+	}
+	return hex.DecodeString(s)
+}
+
+func decodeURL(s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && (i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2])) {
+			return nil, &DecodeError{"url", i}
+		}
+	}
+	v, err := url.QueryUnescape(s)
+	return []byte(v), err
+}
+
+func encodeQuotedPrintable(s string) string {
+	var buf bytes.Buffer
+	w := quotedprintable.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.String()
+}
+
+// decodeQuotedPrintable checks every "=" starts either a hex escape or a
+// soft line break before handing the input to the mime decoder.
+func decodeQuotedPrintable(s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '=' {
+			continue
+		}
+		rest := strings.TrimLeft(s[i+1:], " \t")
+		switch {
+		case strings.HasPrefix(rest, "\r\n"), strings.HasPrefix(rest, "\n"), rest == "":
+		case len(s) > i+2 && isHex(s[i+1]) && isHex(s[i+2]):
+		default:
+			return nil, &DecodeError{"quoted-printable", i}
+		}
+	}
+	return ioutil.ReadAll(quotedprintable.NewReader(strings.NewReader(s)))
+}
@@ -2,12 +2,17 @@ package str
 
 import (
 	"context"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/go-kit/kit/endpoint"
 )
 
 type uppercaseRequest struct {
-	S string `json:"s"`
+	S      string `json:"s"`
+	Locale string `json:"locale,omitempty"`
 }
 
 type uppercaseResponse struct {
@@ -15,18 +20,162 @@ type uppercaseResponse struct {
 	Err string `json:"err,omitempty"` // errors don't JSON-marshal, so we use a string
 }
 
+type transformRequest struct {
+	S      string `json:"s"`
+	Locale string `json:"locale,omitempty"`
+}
+
+type normalizeRequest struct {
+	S    string `json:"s"`
+	Form string `json:"form,omitempty"`
+}
+
+type transformResponse struct {
+	V   string `json:"v"`
+	Err string `json:"err,omitempty"`
+}
+
+type caseRequest struct {
+	S         string `json:"s"`
+	Style     string `json:"style"`
+	Separator string `json:"separator,omitempty"`
+	MaxLength int    `json:"max_length,omitempty"`
+}
+
+type wrapRequest struct {
+	S       string `json:"s"`
+	Width   int    `json:"width"`
+	Align   string `json:"align,omitempty"`
+	Indent  int    `json:"indent,omitempty"`
+	Hanging int    `json:"hanging,omitempty"`
+}
+
 type countRequest struct {
-	S string `json:"s"`
+	S    string `json:"s"`
+	Unit string `json:"unit,omitempty"`
 }
 
 type countResponse struct {
-	V int `json:"v"`
+	V   int     `json:"v"`
+	All *Counts `json:"all,omitempty"`
+	Err string  `json:"err,omitempty"`
+}
+
+// pipelineOp names a Service method and carries the arguments it needs
+// beyond the string being transformed.
+type pipelineOp struct {
+	Op        string `json:"op"`
+	Locale    string `json:"locale,omitempty"`
+	Form      string `json:"form,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+	Scheme    string `json:"scheme,omitempty"`
+	Style     string `json:"style,omitempty"`
+	Separator string `json:"separator,omitempty"`
+	MaxLength int    `json:"max_length,omitempty"`
+	Cutset    string `json:"cutset,omitempty"`
+	Old       string `json:"old,omitempty"`
+	New       string `json:"new,omitempty"`
+	N         int    `json:"n,omitempty"`
+}
+
+type pipelineRequest struct {
+	Inputs []string     `json:"inputs"`
+	Ops    []pipelineOp `json:"ops"`
+}
+
+type pipelineResult struct {
+	V   string `json:"v"`
+	Err string `json:"err,omitempty"`
+}
+
+type pipelineResponse struct {
+	Results []pipelineResult `json:"results"`
+	Err     string           `json:"err,omitempty"`
+}
+
+type diffRequest struct {
+	A       string `json:"a"`
+	B       string `json:"b"`
+	Unit    string `json:"unit,omitempty"`
+	Format  string `json:"format,omitempty"`
+	Context *int   `json:"context,omitempty"`
+}
+
+type diffResponse struct {
+	Hunks   []Hunk `json:"hunks,omitempty"`
+	Unified string `json:"unified,omitempty"`
+	Err     string `json:"err,omitempty"`
+}
+
+type similarityRequest struct {
+	A string `json:"a"`
+	B string `json:"b"`
+}
+
+type matchRequest struct {
+	Query      string   `json:"query"`
+	Candidates []string `json:"candidates"`
+	Metric     string   `json:"metric,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+}
+
+type similarityResponse struct {
+	*Similarity
+	Err string `json:"err,omitempty"`
+}
+
+type matchResponse struct {
+	Matches []Match `json:"matches"`
+	Err     string  `json:"err,omitempty"`
+}
+
+type codecRequest struct {
+	S      string `json:"s"`
+	Scheme string `json:"scheme"`
+}
+
+// decodeResponse carries binary results that are not valid UTF-8 as hex,
+// since JSON strings cannot hold them.
+type decodeResponse struct {
+	V   string `json:"v"`
+	Hex string `json:"hex,omitempty"`
+	Err string `json:"err,omitempty"`
+}
+
+type regexRequest struct {
+	Mode     string `json:"mode"`
+	Pattern  string `json:"pattern"`
+	S        string `json:"s"`
+	Template string `json:"template,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+}
+
+type regexResponse struct {
+	Match   *bool        `json:"match,omitempty"`
+	Matches []RegexMatch `json:"matches,omitempty"`
+	V       *string      `json:"v,omitempty"`
+	Err     string       `json:"err,omitempty"`
+}
+
+type statsRequest struct {
+	S   string `json:"s"`
+	Top int    `json:"top,omitempty"`
+}
+
+type languageRequest struct {
+	S string `json:"s"`
+	K int    `json:"k,omitempty"`
+}
+
+type languageResponse struct {
+	Languages []LanguageScore `json:"languages,omitempty"`
+	Err       string          `json:"err,omitempty"`
 }
 
 func makeUppercaseEndpoint(svc Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(uppercaseRequest)
-		v, err := svc.Uppercase(req.S)
+		v, err := svc.Uppercase(req.S, req.Locale)
 		if err != nil {
 			return uppercaseResponse{v, err.Error()}, nil
 		}
@@ -34,10 +183,310 @@ func makeUppercaseEndpoint(svc Service) endpoint.Endpoint {
 	}
 }
 
+func makeLowercaseEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transformRequest)
+		v, err := svc.Lowercase(req.S, req.Locale)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+func makeTitleEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transformRequest)
+		v, err := svc.Title(req.S, req.Locale)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+func makeFoldEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transformRequest)
+		v, err := svc.Fold(req.S, req.Locale)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+func makeNormalizeEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(normalizeRequest)
+		v, err := svc.Normalize(req.S, req.Form)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+func makeReverseEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(transformRequest)
+		v, err := svc.Reverse(req.S)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+// makeCaseEndpoint treats the "slug" style as a request to Slugify.
+func makeCaseEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(caseRequest)
+		var (
+			v   string
+			err error
+		)
+		if strings.EqualFold(req.Style, "slug") {
+			v, err = svc.Slugify(req.S, req.Separator, req.MaxLength)
+		} else {
+			v, err = svc.ConvertCase(req.S, req.Style)
+		}
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+func makeWrapEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(wrapRequest)
+		v, err := svc.Wrap(req.S, req.Width, req.Align, req.Indent, req.Hanging)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
 func makeCountEndpoint(svc Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(countRequest)
-		v := svc.Count(req.S)
-		return countResponse{v}, nil
+		if strings.EqualFold(req.Unit, "all") {
+			all := svc.CountAll(req.S)
+			return countResponse{all.Bytes, &all, ""}, nil
+		}
+		v, err := svc.Count(req.S, req.Unit)
+		if err != nil {
+			return countResponse{v, nil, err.Error()}, nil
+		}
+		return countResponse{v, nil, ""}, nil
+	}
+}
+
+// Limits on a pipeline request. Each op may grow its input, so the result
+// of every op is held to maxPipelineOutput bytes.
+const (
+	maxPipelineInputs = 1000
+	maxPipelineOps    = 32
+	maxPipelineOutput = 4 << 20
+)
+
+// makePipelineEndpoint runs every input through the ops in order, stopping at
+// the first error for that input. A count op yields its result as a decimal
+// string so it can be followed by further ops.
+func makePipelineEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(pipelineRequest)
+		if len(req.Inputs) > maxPipelineInputs {
+			return pipelineResponse{Err: ErrTooManyInputs.Error()}, nil
+		}
+		if len(req.Ops) > maxPipelineOps {
+			return pipelineResponse{Err: ErrTooManyOps.Error()}, nil
+		}
+		results := make([]pipelineResult, len(req.Inputs))
+		for i, s := range req.Inputs {
+			var err error
+			for _, op := range req.Ops {
+				if err = ctx.Err(); err != nil {
+					return pipelineResponse{Err: err.Error()}, nil
+				}
+				if s, err = applyOp(svc, op, s); err != nil {
+					break
+				}
+				if len(s) > maxPipelineOutput {
+					err = ErrOutputTooLarge
+					break
+				}
+			}
+			if err != nil {
+				results[i] = pipelineResult{"", err.Error()}
+				continue
+			}
+			results[i] = pipelineResult{s, ""}
+		}
+		return pipelineResponse{results, ""}, nil
+	}
+}
+
+func applyOp(svc Service, op pipelineOp, s string) (string, error) {
+	switch strings.ToLower(op.Op) {
+	case "uppercase":
+		return svc.Uppercase(s, op.Locale)
+	case "lowercase":
+		return svc.Lowercase(s, op.Locale)
+	case "title":
+		return svc.Title(s, op.Locale)
+	case "fold":
+		return svc.Fold(s, op.Locale)
+	case "normalize":
+		return svc.Normalize(s, op.Form)
+	case "reverse":
+		return svc.Reverse(s)
+	case "case":
+		if strings.EqualFold(op.Style, "slug") {
+			return svc.Slugify(s, op.Separator, op.MaxLength)
+		}
+		return svc.ConvertCase(s, op.Style)
+	case "trim":
+		return svc.Trim(s, op.Cutset), nil
+	case "replace":
+		return svc.Replace(s, op.Old, op.New, op.N)
+	case "encode":
+		return svc.Encode(s, op.Scheme)
+	case "decode":
+		b, err := svc.Decode(s, op.Scheme)
+		return string(b), err
+	case "count":
+		n, err := svc.Count(s, op.Unit)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Itoa(n), nil
+	default:
+		return "", ErrOp
+	}
+}
+
+func makeDiffEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(diffRequest)
+		lines := 3
+		if req.Context != nil {
+			lines = *req.Context
+		}
+		hs, err := svc.Diff(req.A, req.B, req.Unit, lines)
+		if err != nil {
+			return diffResponse{Err: err.Error()}, nil
+		}
+		switch strings.ToLower(req.Format) {
+		case "", "json":
+			return diffResponse{Hunks: hs}, nil
+		case "unified":
+			return diffResponse{Unified: Unified(hs)}, nil
+		default:
+			return diffResponse{Err: ErrFormat.Error()}, nil
+		}
+	}
+}
+
+func makeSimilarityEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(similarityRequest)
+		sim, err := svc.Similarity(req.A, req.B)
+		if err != nil {
+			return similarityResponse{nil, err.Error()}, nil
+		}
+		return similarityResponse{&sim, ""}, nil
+	}
+}
+
+func makeMatchEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(matchRequest)
+		ms, err := svc.Match(ctx, req.Query, req.Candidates, req.Metric, req.Limit)
+		if err != nil {
+			return matchResponse{nil, err.Error()}, nil
+		}
+		return matchResponse{ms, ""}, nil
+	}
+}
+
+func makeEncodeEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(codecRequest)
+		v, err := svc.Encode(req.S, req.Scheme)
+		if err != nil {
+			return transformResponse{v, err.Error()}, nil
+		}
+		return transformResponse{v, ""}, nil
+	}
+}
+
+func makeDecodeEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(codecRequest)
+		b, err := svc.Decode(req.S, req.Scheme)
+		if err != nil {
+			return decodeResponse{"", "", err.Error()}, nil
+		}
+		if !utf8.Valid(b) {
+			return decodeResponse{"", hex.EncodeToString(b), ""}, nil
+		}
+		return decodeResponse{string(b), "", ""}, nil
+	}
+}
+
+func makeRegexEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(regexRequest)
+		switch strings.ToLower(req.Mode) {
+		case "", "test":
+			ok, err := svc.RegexTest(req.Pattern, req.S)
+			if err != nil {
+				return regexResponse{Err: err.Error()}, nil
+			}
+			return regexResponse{Match: &ok}, nil
+		case "find":
+			ms, err := svc.RegexFind(req.Pattern, req.S, req.Limit)
+			if err != nil {
+				return regexResponse{Err: err.Error()}, nil
+			}
+			return regexResponse{Matches: ms}, nil
+		case "replace":
+			v, err := svc.RegexReplace(req.Pattern, req.S, req.Template)
+			if err != nil {
+				return regexResponse{Err: err.Error()}, nil
+			}
+			return regexResponse{V: &v}, nil
+		default:
+			return regexResponse{Err: ErrMode.Error()}, nil
+		}
+	}
+}
+
+func makeStatsEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(statsRequest)
+		top := req.Top
+		if top == 0 {
+			top = 10
+		}
+		return svc.Stats(req.S, top), nil
+	}
+}
+
+func makeLanguageEndpoint(svc Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(languageRequest)
+		k := req.K
+		if k == 0 {
+			k = 3
+		}
+		scores, err := svc.DetectLanguage(req.S, k)
+		if err != nil {
+			return languageResponse{nil, err.Error()}, nil
+		}
+		return languageResponse{scores, ""}, nil
 	}
 }
@@ -0,0 +1,241 @@
+package str
+
+import (
+	_ "embed"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed langprofiles.txt
+var langProfileData string
+
+// maxTrigrams is both the size of each bundled profile and the penalty for
+// a trigram the profile lacks.
+const maxTrigrams = 300
+
+// langTemperature sharpens the softmax over normalized profile distances,
+// in which a gap of 0.01 between two languages is already significant.
+const langTemperature = 0.01
+
+// LanguageScore is a detected language. Code is ISO 639-3 and Tag the
+// shortest BCP 47 form of it.
+type LanguageScore struct {
+	Code       string  `json:"code"`
+	Tag        string  `json:"tag"`
+	Confidence float64 `json:"confidence"`
+}
+
+type langProfile struct {
+	code  string
+	ranks map[string]int
+}
+
+var langProfiles = parseLangProfiles(langProfileData)
+
+// scriptLangs maps scripts that are written by a single supported language
+// straight to that language.
+var scriptLangs = []struct {
+	script *unicode.RangeTable
+	code   string
+}{
+	{unicode.Hangul, "kor"},
+	{unicode.Hiragana, "jpn"},
+	{unicode.Katakana, "jpn"},
+	{unicode.Han, "cmn"},
+	{unicode.Greek, "ell"},
+	{unicode.Georgian, "kat"},
+	{unicode.Bengali, "ben"},
+	{unicode.Gujarati, "guj"},
+	{unicode.Gurmukhi, "pan"},
+	{unicode.Kannada, "kan"},
+	{unicode.Malayalam, "mal"},
+	{unicode.Oriya, "ori"},
+	{unicode.Tamil, "tam"},
+	{unicode.Telugu, "tel"},
+	{unicode.Sinhala, "sin"},
+	{unicode.Thai, "tha"},
+	{unicode.Khmer, "khm"},
+	{unicode.Myanmar, "mya"},
+}
+
+func parseLangProfiles(data string) []langProfile {
+	var profiles []langProfile
+	for _, line := range strings.Split(data, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 2)
+		p := langProfile{fields[0], make(map[string]int)}
+		for i, t := range strings.Split(fields[1], "|") {
+			p.ranks[t] = i
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// scriptLanguage returns the language of a script written by only one
+// language, if letters of such a script outnumber all others. Any kana
+// among Han characters makes the text Japanese.
+func scriptLanguage(s string) (string, bool) {
+	counts := make(map[string]int)
+	other := 0
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		found := false
+		for _, sl := range scriptLangs {
+			if unicode.Is(sl.script, r) {
+				counts[sl.code]++
+				found = true
+				break
+			}
+		}
+		if !found {
+			other++
+		}
+	}
+	if counts["jpn"] > 0 && counts["cmn"] > 0 {
+		counts["jpn"] += counts["cmn"]
+		delete(counts, "cmn")
+	}
+	best, bestCount := "", 0
+	for code, n := range counts {
+		if n > bestCount {
+			best, bestCount = code, n
+		}
+	}
+	return best, bestCount > other
+}
+
+// textTrigram is a trigram of the input with the range of ranks it shares
+// with other trigrams of the same frequency.
+type textTrigram struct {
+	t      string
+	lo, hi int
+}
+
+// textTrigrams returns the most frequent rune trigrams of s after
+// lowercasing it and collapsing everything but letters and marks to single
+// spaces, the same normalization the profiles were built with.
+func textTrigrams(s string) []textTrigram {
+	var b strings.Builder
+	b.WriteByte(' ')
+	space := true
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.Is(unicode.Mn, r) {
+			b.WriteRune(r)
+			space = false
+		} else if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+	if !space {
+		b.WriteByte(' ')
+	}
+
+	rs := []rune(b.String())
+	freq := make(map[string]int)
+	for i := 0; i+3 <= len(rs); i++ {
+		freq[string(rs[i:i+3])]++
+	}
+	keys := make([]string, 0, len(freq))
+	for t := range freq {
+		keys = append(keys, t)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if freq[a] != freq[b] {
+			return freq[a] > freq[b]
+		}
+		return a < b
+	})
+	if len(keys) > maxTrigrams {
+		keys = keys[:maxTrigrams]
+	}
+
+	// the order among equally frequent trigrams is arbitrary, so each may
+	// take any rank of its group
+	trigrams := make([]textTrigram, len(keys))
+	for lo := 0; lo < len(keys); {
+		hi := lo
+		for hi+1 < len(keys) && freq[keys[hi+1]] == freq[keys[lo]] {
+			hi++
+		}
+		for i := lo; i <= hi; i++ {
+			trigrams[i] = textTrigram{keys[i], lo, hi}
+		}
+		lo = hi + 1
+	}
+	return trigrams
+}
+
+// detectLanguage ranks every profile by the out-of-place distance of its
+// trigram ranks from those of s, and turns the distances into confidences
+// with a softmax.
+func detectLanguage(s string) []LanguageScore {
+	if code, ok := scriptLanguage(s); ok {
+		return []LanguageScore{{code, langTag(code), 1}}
+	}
+	trigrams := textTrigrams(s)
+	if len(trigrams) == 0 {
+		return nil
+	}
+
+	// some languages have a profile per script; keep the closest
+	dist := make(map[string]float64)
+	for _, p := range langProfiles {
+		d := 0
+		for _, t := range trigrams {
+			if r, ok := p.ranks[t.t]; ok {
+				if r > t.hi {
+					d += r - t.hi
+				} else if r < t.lo {
+					d += t.lo - r
+				}
+			} else {
+				d += maxTrigrams
+			}
+		}
+		nd := float64(d) / float64(len(trigrams)*maxTrigrams)
+		if old, ok := dist[p.code]; !ok || nd < old {
+			dist[p.code] = nd
+		}
+	}
+
+	best := math.Inf(1)
+	for _, d := range dist {
+		best = math.Min(best, d)
+	}
+	var total float64
+	scores := make([]LanguageScore, 0, len(dist))
+	for code, d := range dist {
+		w := math.Exp(-(d - best) / langTemperature)
+		total += w
+		scores = append(scores, LanguageScore{code, langTag(code), w})
+	}
+	for i := range scores {
+		scores[i].Confidence /= total
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Confidence != scores[j].Confidence {
+			return scores[i].Confidence > scores[j].Confidence
+		}
+		return scores[i].Code < scores[j].Code
+	})
+	return scores
+}
+
+func langTag(code string) string {
+	base, err := language.ParseBase(code)
+	if err != nil {
+		return code
+	}
+	return base.String()
+}
@@ -1,7 +1,9 @@
 package str
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
@@ -43,51 +45,627 @@ type instrumentingMiddleware struct {
 	next           Service
 }
 
-func (mw loggingMiddleware) Uppercase(s string) (output string, err error) {
+func (mw loggingMiddleware) Uppercase(s, locale string) (output string, err error) {
 	defer func(begin time.Time) {
 		mw.logger.Debug(
 			"service",
 			zap.String("method", "Uppercase"),
 			zap.String("input", s),
+			zap.String("locale", locale),
 			zap.String("output", output),
 			zap.Duration("took", time.Since(begin)),
 			zap.Error(err),
 		)
 	}(time.Now())
-	output, err = mw.next.Uppercase(s)
+	output, err = mw.next.Uppercase(s, locale)
 	return
 }
 
-func (mw loggingMiddleware) Count(s string) (n int) {
+func (mw loggingMiddleware) UppercaseStream(dst io.Writer, src io.Reader, locale string) (n int64, err error) {
 	defer func(begin time.Time) {
-		mw.logger.Info(
+		mw.logger.Debug(
 			"service",
-			zap.String("method", "Count"),
+			zap.String("method", "UppercaseStream"),
+			zap.String("locale", locale),
+			zap.Int64("written", n),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	n, err = mw.next.UppercaseStream(dst, src, locale)
+	return
+}
+
+func (mw loggingMiddleware) Lowercase(s, locale string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Lowercase"),
+			zap.String("input", s),
+			zap.String("locale", locale),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Lowercase(s, locale)
+	return
+}
+
+func (mw loggingMiddleware) Title(s, locale string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Title"),
+			zap.String("input", s),
+			zap.String("locale", locale),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Title(s, locale)
+	return
+}
+
+func (mw loggingMiddleware) Fold(s, locale string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Fold"),
+			zap.String("input", s),
+			zap.String("locale", locale),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Fold(s, locale)
+	return
+}
+
+func (mw loggingMiddleware) Normalize(s, form string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Normalize"),
+			zap.String("input", s),
+			zap.String("form", form),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Normalize(s, form)
+	return
+}
+
+func (mw loggingMiddleware) Reverse(s string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Reverse"),
+			zap.String("input", s),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Reverse(s)
+	return
+}
+
+func (mw loggingMiddleware) ConvertCase(s, style string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "ConvertCase"),
+			zap.String("input", s),
+			zap.String("style", style),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.ConvertCase(s, style)
+	return
+}
+
+func (mw loggingMiddleware) Slugify(s, separator string, maxLen int) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Slugify"),
+			zap.String("input", s),
+			zap.String("separator", separator),
+			zap.Int("max_length", maxLen),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Slugify(s, separator, maxLen)
+	return
+}
+
+func (mw loggingMiddleware) Wrap(s string, width int, align string, indent, hanging int) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Wrap"),
+			zap.Int("size", len(s)),
+			zap.Int("width", width),
+			zap.String("align", align),
+			zap.Int("indent", indent),
+			zap.Int("hanging", hanging),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Wrap(s, width, align, indent, hanging)
+	return
+}
+
+func (mw loggingMiddleware) Trim(s, cutset string) (output string) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Trim"),
 			zap.String("input", s),
+			zap.String("cutset", cutset),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+		)
+	}(time.Now())
+	output = mw.next.Trim(s, cutset)
+	return
+}
+
+func (mw loggingMiddleware) Replace(s, old, new string, n int) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Replace"),
+			zap.String("input", s),
+			zap.String("old", old),
+			zap.String("new", new),
+			zap.Int("n", n),
+			zap.String("output", output),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Replace(s, old, new, n)
+	return
+}
+
+func (mw loggingMiddleware) Count(s, unit string) (n int, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Count"),
+			zap.Int("size", len(s)),
+			zap.String("unit", unit),
 			zap.Int("output", n),
 			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	n, err = mw.next.Count(s, unit)
+	return
+}
+
+func (mw loggingMiddleware) CountAll(s string) (c Counts) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "CountAll"),
+			zap.Int("size", len(s)),
+			zap.Any("output", c),
+			zap.Duration("took", time.Since(begin)),
+		)
+	}(time.Now())
+	c = mw.next.CountAll(s)
+	return
+}
+
+func (mw loggingMiddleware) Diff(a, b, unit string, context int) (hs []Hunk, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Diff"),
+			zap.Int("a_len", len(a)),
+			zap.Int("b_len", len(b)),
+			zap.String("unit", unit),
+			zap.Int("context", context),
+			zap.Int("hunks", len(hs)),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	hs, err = mw.next.Diff(a, b, unit, context)
+	return
+}
+
+func (mw loggingMiddleware) Similarity(a, b string) (sim Similarity, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Similarity"),
+			zap.String("a", a),
+			zap.String("b", b),
+			zap.Any("output", sim),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	sim, err = mw.next.Similarity(a, b)
+	return
+}
+
+func (mw loggingMiddleware) Match(ctx context.Context, query string, candidates []string, metric string, limit int) (ms []Match, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Match"),
+			zap.String("query", query),
+			zap.Int("candidates", len(candidates)),
+			zap.String("metric", metric),
+			zap.Int("limit", limit),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	ms, err = mw.next.Match(ctx, query, candidates, metric, limit)
+	return
+}
+
+func (mw loggingMiddleware) Encode(s, scheme string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Encode"),
+			zap.String("scheme", scheme),
+			zap.Int("size", len(s)),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Encode(s, scheme)
+	return
+}
+
+func (mw loggingMiddleware) Decode(s, scheme string) (output []byte, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Decode"),
+			zap.String("scheme", scheme),
+			zap.Int("size", len(s)),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.Decode(s, scheme)
+	return
+}
+
+func (mw loggingMiddleware) RegexTest(pattern, s string) (ok bool, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "RegexTest"),
+			zap.String("pattern", pattern),
+			zap.Int("size", len(s)),
+			zap.Bool("output", ok),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	ok, err = mw.next.RegexTest(pattern, s)
+	return
+}
+
+func (mw loggingMiddleware) RegexFind(pattern, s string, limit int) (ms []RegexMatch, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "RegexFind"),
+			zap.String("pattern", pattern),
+			zap.Int("size", len(s)),
+			zap.Int("limit", limit),
+			zap.Int("matches", len(ms)),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
 		)
 	}(time.Now())
-	n = mw.next.Count(s)
+	ms, err = mw.next.RegexFind(pattern, s, limit)
 	return
 }
 
-func (mw instrumentingMiddleware) Uppercase(s string) (output string, err error) {
+func (mw loggingMiddleware) RegexReplace(pattern, s, template string) (output string, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "RegexReplace"),
+			zap.String("pattern", pattern),
+			zap.String("template", template),
+			zap.Int("size", len(s)),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	output, err = mw.next.RegexReplace(pattern, s, template)
+	return
+}
+
+func (mw loggingMiddleware) Stats(s string, top int) (stats TextStats) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "Stats"),
+			zap.Int("size", len(s)),
+			zap.Int("top", top),
+			zap.Int("words", stats.Words),
+			zap.Duration("took", time.Since(begin)),
+		)
+	}(time.Now())
+	stats = mw.next.Stats(s, top)
+	return
+}
+
+func (mw loggingMiddleware) DetectLanguage(s string, k int) (scores []LanguageScore, err error) {
+	defer func(begin time.Time) {
+		mw.logger.Debug(
+			"service",
+			zap.String("method", "DetectLanguage"),
+			zap.Int("size", len(s)),
+			zap.Int("k", k),
+			zap.Any("output", scores),
+			zap.Duration("took", time.Since(begin)),
+			zap.Error(err),
+		)
+	}(time.Now())
+	scores, err = mw.next.DetectLanguage(s, k)
+	return
+}
+
+func (mw instrumentingMiddleware) Uppercase(s, locale string) (output string, err error) {
 	defer func(begin time.Time) {
 		lvs := []string{"method", "uppercase", "error", fmt.Sprint(err != nil)}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
-	output, err = mw.next.Uppercase(s)
+	output, err = mw.next.Uppercase(s, locale)
+	return
+}
+
+func (mw instrumentingMiddleware) UppercaseStream(dst io.Writer, src io.Reader, locale string) (n int64, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "uppercasestream", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	n, err = mw.next.UppercaseStream(dst, src, locale)
+	return
+}
+
+func (mw instrumentingMiddleware) Lowercase(s, locale string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "lowercase", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Lowercase(s, locale)
+	return
+}
+
+func (mw instrumentingMiddleware) Title(s, locale string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "title", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Title(s, locale)
+	return
+}
+
+func (mw instrumentingMiddleware) Fold(s, locale string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "fold", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Fold(s, locale)
+	return
+}
+
+func (mw instrumentingMiddleware) Normalize(s, form string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "normalize", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Normalize(s, form)
+	return
+}
+
+func (mw instrumentingMiddleware) Reverse(s string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "reverse", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Reverse(s)
+	return
+}
+
+func (mw instrumentingMiddleware) ConvertCase(s, style string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "convertcase", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.ConvertCase(s, style)
+	return
+}
+
+func (mw instrumentingMiddleware) Slugify(s, separator string, maxLen int) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "slugify", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Slugify(s, separator, maxLen)
+	return
+}
+
+func (mw instrumentingMiddleware) Wrap(s string, width int, align string, indent, hanging int) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "wrap", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Wrap(s, width, align, indent, hanging)
+	return
+}
+
+func (mw instrumentingMiddleware) Trim(s, cutset string) (output string) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "trim", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output = mw.next.Trim(s, cutset)
+	return
+}
+
+func (mw instrumentingMiddleware) Replace(s, old, new string, n int) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "replace", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Replace(s, old, new, n)
+	return
+}
+
+func (mw instrumentingMiddleware) Count(s, unit string) (n int, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "count", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	n, err = mw.next.Count(s, unit)
+	return
+}
+
+func (mw instrumentingMiddleware) CountAll(s string) (c Counts) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "countall", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	c = mw.next.CountAll(s)
+	return
+}
+
+func (mw instrumentingMiddleware) Diff(a, b, unit string, context int) (hs []Hunk, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "diff", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	hs, err = mw.next.Diff(a, b, unit, context)
+	return
+}
+
+func (mw instrumentingMiddleware) Similarity(a, b string) (sim Similarity, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "similarity", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	sim, err = mw.next.Similarity(a, b)
+	return
+}
+
+func (mw instrumentingMiddleware) Match(ctx context.Context, query string, candidates []string, metric string, limit int) (ms []Match, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "match", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	ms, err = mw.next.Match(ctx, query, candidates, metric, limit)
+	return
+}
+
+func (mw instrumentingMiddleware) Encode(s, scheme string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "encode", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Encode(s, scheme)
+	return
+}
+
+func (mw instrumentingMiddleware) Decode(s, scheme string) (output []byte, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "decode", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.Decode(s, scheme)
+	return
+}
+
+func (mw instrumentingMiddleware) RegexTest(pattern, s string) (ok bool, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "regextest", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	ok, err = mw.next.RegexTest(pattern, s)
+	return
+}
+
+func (mw instrumentingMiddleware) RegexFind(pattern, s string, limit int) (ms []RegexMatch, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "regexfind", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	ms, err = mw.next.RegexFind(pattern, s, limit)
+	return
+}
+
+func (mw instrumentingMiddleware) RegexReplace(pattern, s, template string) (output string, err error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "regexreplace", "error", fmt.Sprint(err != nil)}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	output, err = mw.next.RegexReplace(pattern, s, template)
+	return
+}
+
+func (mw instrumentingMiddleware) Stats(s string, top int) (stats TextStats) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "stats", "error", "false"}
+		mw.requestCount.With(lvs...).Add(1)
+		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	stats = mw.next.Stats(s, top)
 	return
 }
 
-func (mw instrumentingMiddleware) Count(s string) (n int) {
+func (mw instrumentingMiddleware) DetectLanguage(s string, k int) (scores []LanguageScore, err error) {
 	defer func(begin time.Time) {
-		lvs := []string{"method", "count", "error", "false"}
+		lvs := []string{"method", "detectlanguage", "error", fmt.Sprint(err != nil)}
 		mw.requestCount.With(lvs...).Add(1)
 		mw.requestLatency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
-	n = mw.next.Count(s)
+	scores, err = mw.next.DetectLanguage(s, k)
 	return
 }
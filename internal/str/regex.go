@@ -0,0 +1,95 @@
+package str
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Limits applied to every regex request. Go's RE2 engine runs in time linear
+// in the input, so these bound the work done per request.
+const (
+	maxPatternLen   = 1024
+	maxRegexInput   = 1 << 20
+	maxRegexMatches = 1000
+	// maxReplaceOutput bounds the result of a replacement, since a short
+	// template repeated over many matches can expand far past the input.
+	maxReplaceOutput = 4 << 20
+)
+
+// RegexMatch is one match with its capture groups. Offsets are in bytes,
+// and groups that did not participate in the match have offsets of -1.
+type RegexMatch struct {
+	Text   string       `json:"text"`
+	Start  int          `json:"start"`
+	End    int          `json:"end"`
+	Groups []RegexGroup `json:"groups,omitempty"`
+}
+
+type RegexGroup struct {
+	Name  string `json:"name,omitempty"`
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+func compileRegex(pattern, s string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, ErrEmpty
+	}
+	if len(pattern) > maxPatternLen {
+		return nil, ErrPatternTooLong
+	}
+	if len(s) > maxRegexInput {
+		return nil, ErrInputTooLarge
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrPattern, err)
+	}
+	return re, nil
+}
+
+func regexMatches(re *regexp.Regexp, s string, idx [][]int) []RegexMatch {
+	names := re.SubexpNames()
+	matches := make([]RegexMatch, len(idx))
+	for i, loc := range idx {
+		m := RegexMatch{Text: s[loc[0]:loc[1]], Start: loc[0], End: loc[1]}
+		for g := 1; g < len(names); g++ {
+			start, end := loc[2*g], loc[2*g+1]
+			group := RegexGroup{Name: names[g], Start: start, End: end}
+			if start >= 0 {
+				group.Text = s[start:end]
+			}
+			m.Groups = append(m.Groups, group)
+		}
+		matches[i] = m
+	}
+	return matches
+}
+
+// regexReplace expands template for each match of re in s, giving up once
+// there are too many matches or the result grows too large.
+func regexReplace(re *regexp.Regexp, s, template string) (string, error) {
+	if len(template) > maxPatternLen {
+		return "", ErrTemplateTooLong
+	}
+	idx := re.FindAllStringSubmatchIndex(s, maxRegexMatches+1)
+	if len(idx) > maxRegexMatches {
+		return "", ErrTooManyMatches
+	}
+	var out []byte
+	last := 0
+	for _, loc := range idx {
+		out = append(out, s[last:loc[0]]...)
+		out = re.ExpandString(out, template, s, loc)
+		if len(out) > maxReplaceOutput {
+			return "", ErrOutputTooLarge
+		}
+		last = loc[1]
+	}
+	out = append(out, s[last:]...)
+	if len(out) > maxReplaceOutput {
+		return "", ErrOutputTooLarge
+	}
+	return string(out), nil
+}
@@ -1,28 +1,480 @@
 package str
 
 import (
+	"context"
 	"errors"
+	"io"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 type Service interface {
-	Uppercase(string) (string, error)
-	Count(string) int
+	Uppercase(s, locale string) (string, error)
+	UppercaseStream(dst io.Writer, src io.Reader, locale string) (int64, error)
+	Lowercase(s, locale string) (string, error)
+	Title(s, locale string) (string, error)
+	Fold(s, locale string) (string, error)
+	Normalize(s, form string) (string, error)
+	Reverse(string) (string, error)
+	ConvertCase(s, style string) (string, error)
+	Slugify(s, separator string, maxLen int) (string, error)
+	Wrap(s string, width int, align string, indent, hanging int) (string, error)
+	Trim(s, cutset string) string
+	Replace(s, old, new string, n int) (string, error)
+	Count(s, unit string) (int, error)
+	CountAll(string) Counts
+	Stats(s string, top int) TextStats
+	DetectLanguage(s string, k int) ([]LanguageScore, error)
+	Diff(a, b, unit string, context int) ([]Hunk, error)
+	Similarity(a, b string) (Similarity, error)
+	Encode(s, scheme string) (string, error)
+	Decode(s, scheme string) ([]byte, error)
+	RegexTest(pattern, s string) (bool, error)
+	RegexFind(pattern, s string, limit int) ([]RegexMatch, error)
+	RegexReplace(pattern, s, template string) (string, error)
+	Match(ctx context.Context, query string, candidates []string, metric string, limit int) ([]Match, error)
+}
+
+// Counts holds the length of a string in every supported unit.
+type Counts struct {
+	Bytes     int `json:"bytes"`
+	Runes     int `json:"runes"`
+	Graphemes int `json:"graphemes"`
+	Words     int `json:"words"`
+	Lines     int `json:"lines"`
+	Sentences int `json:"sentences"`
 }
 
-var ErrEmpty = errors.New("Empty string")
+var (
+	ErrEmpty  = errors.New("Empty string")
+	ErrLocale = errors.New("Unknown locale")
+	ErrForm   = errors.New("Unknown normalization form")
+	ErrUnit   = errors.New("Unknown counting unit")
+	ErrOp     = errors.New("Unknown operation")
+	ErrStyle  = errors.New("Unknown case style")
+	ErrNoText = errors.New("No letters to detect a language from")
+	ErrWidth  = errors.New("Invalid wrap width or indent")
+	ErrAlign  = errors.New("Unknown alignment")
+	ErrFormat = errors.New("Unknown output format")
+
+	ErrTooManyInputs = errors.New("Too many pipeline inputs")
+	ErrTooManyOps    = errors.New("Too many pipeline ops")
+
+	ErrMetric            = errors.New("Unknown similarity metric")
+	ErrCompareTooLong    = errors.New("Input too long to compare")
+	ErrTooManyCandidates = errors.New("Too many candidates to match")
+	ErrScheme            = errors.New("Unknown encoding scheme")
+
+	ErrMode            = errors.New("Unknown regex mode")
+	ErrPattern         = errors.New("Invalid pattern")
+	ErrPatternTooLong  = errors.New("Pattern too long")
+	ErrInputTooLarge   = errors.New("Input too large")
+	ErrTooManyMatches  = errors.New("Too many matches")
+	ErrTemplateTooLong = errors.New("Replacement template too long")
+	ErrOutputTooLarge  = errors.New("Result too large")
+
+	ErrDiffUnit     = errors.New("Unknown diff unit")
+	ErrDiffTooLarge = errors.New("Texts differ too much to diff")
+)
 
 type service struct{}
 
-func (service) Uppercase(s string) (string, error) {
+func (service) Uppercase(s, locale string) (string, error) {
 	if s == "" {
 		return "", ErrEmpty
 	}
-	return strings.ToUpper(s), nil
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return cases.Upper(tag).String(s), nil
 }
 
-func (service) Count(s string) int {
-	return len(s)
+// UppercaseStream uppercases src into dst without buffering it whole. The
+// transform reader only hands complete runes to the caser, so multi-byte
+// sequences are never split across chunks.
+func (service) UppercaseStream(dst io.Writer, src io.Reader, locale string) (int64, error) {
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return 0, err
+	}
+	return io.Copy(dst, transform.NewReader(src, cases.Upper(tag)))
+}
+
+func (service) Lowercase(s, locale string) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return cases.Lower(tag).String(s), nil
+}
+
+func (service) Title(s, locale string) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	return cases.Title(tag).String(s), nil
+}
+
+// Fold applies Unicode case folding. The only locale-sensitive folding rules
+// are the Turkic dotted and dotless i, which we get by lowering first.
+func (service) Fold(s, locale string) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	tag, err := parseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+	if base, _ := tag.Base(); base.String() == "tr" || base.String() == "az" {
+		s = cases.Lower(tag).String(s)
+	}
+	return cases.Fold().String(s), nil
+}
+
+func (service) Normalize(s, form string) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	var f norm.Form
+	switch strings.ToUpper(form) {
+	case "", "NFC":
+		f = norm.NFC
+	case "NFD":
+		f = norm.NFD
+	case "NFKC":
+		f = norm.NFKC
+	case "NFKD":
+		f = norm.NFKD
+	default:
+		return "", ErrForm
+	}
+	return f.String(s), nil
+}
+
+// Reverse reverses s by grapheme cluster so combining marks and emoji
+// sequences stay attached to their base characters.
+func (service) Reverse(s string) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	return uniseg.ReverseString(s), nil
+}
+
+// ConvertCase rewrites s, split into words at punctuation and case changes,
+// in one of the camel, pascal, snake, screaming_snake, kebab or title styles.
+func (service) ConvertCase(s, style string) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	return convertCase(s, style)
+}
+
+// Slugify folds s to lowercase ASCII words joined by separator, "-" by
+// default, and cuts it to at most maxLen bytes when maxLen is positive.
+func (service) Slugify(s, separator string, maxLen int) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	if separator == "" {
+		separator = "-"
+	}
+	return slugify(s, separator, maxLen), nil
+}
+
+// Wrap fills each line of s to at most width display columns, counting East
+// Asian wide characters and emoji as two. The first line of every paragraph
+// is indented by indent columns and the rest by hanging columns. Width may
+// be at most maxWrapWidth.
+func (service) Wrap(s string, width int, align string, indent, hanging int) (string, error) {
+	if s == "" {
+		return "", ErrEmpty
+	}
+	if width > maxWrapWidth || indent < 0 || hanging < 0 || indent >= width || hanging >= width {
+		return "", ErrWidth
+	}
+	align = strings.ToLower(align)
+	switch align {
+	case "", "left", "right", "center", "justify":
+	default:
+		return "", ErrAlign
+	}
+	return wrapText(s, width, align, indent, hanging), nil
+}
+
+// Trim removes leading and trailing runes in cutset, or white space when
+// cutset is empty.
+func (service) Trim(s, cutset string) string {
+	if cutset == "" {
+		return strings.TrimSpace(s)
+	}
+	return strings.Trim(s, cutset)
+}
+
+// Replace replaces the first n instances of old with new, or all of them
+// when n is not positive. It fails rather than return a result over
+// maxReplaceOutput bytes.
+func (service) Replace(s, old, new string, n int) (string, error) {
+	count := strings.Count(s, old)
+	if n <= 0 || n > count {
+		n = count
+	}
+	if len(s)+n*(len(new)-len(old)) > maxReplaceOutput {
+		return "", ErrOutputTooLarge
+	}
+	return strings.Replace(s, old, new, n), nil
+}
+
+// Count returns the length of s in the given unit, defaulting to bytes.
+func (service) Count(s, unit string) (int, error) {
+	switch strings.ToLower(unit) {
+	case "", "bytes":
+		return len(s), nil
+	case "runes":
+		return utf8.RuneCountInString(s), nil
+	case "graphemes":
+		return uniseg.GraphemeClusterCount(s), nil
+	case "words":
+		return countWords(s), nil
+	case "lines":
+		return countLines(s), nil
+	case "sentences":
+		return countSentences(s), nil
+	default:
+		return 0, ErrUnit
+	}
+}
+
+// Stats returns word, sentence and readability statistics for s, with the
+// top most frequent words; top <= 0 returns every word.
+func (service) Stats(s string, top int) TextStats {
+	return textStats(s, top)
+}
+
+// DetectLanguage returns the k most likely languages of s, most likely
+// first; k <= 0 returns every candidate.
+func (service) DetectLanguage(s string, k int) ([]LanguageScore, error) {
+	if s == "" {
+		return nil, ErrEmpty
+	}
+	scores := detectLanguage(s)
+	if len(scores) == 0 {
+		return nil, ErrNoText
+	}
+	if k > 0 && k < len(scores) {
+		scores = scores[:k]
+	}
+	return scores, nil
+}
+
+func (service) CountAll(s string) Counts {
+	return Counts{
+		Bytes:     len(s),
+		Runes:     utf8.RuneCountInString(s),
+		Graphemes: uniseg.GraphemeClusterCount(s),
+		Words:     countWords(s),
+		Lines:     countLines(s),
+		Sentences: countSentences(s),
+	}
+}
+
+// countWords skips punctuation and white space segments.
+func countWords(s string) int {
+	return len(words(s))
+}
+
+// countLines counts newline-terminated lines plus a trailing unterminated one.
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	n := strings.Count(s, "\n")
+	if !strings.HasSuffix(s, "\n") {
+		n++
+	}
+	return n
+}
+
+func countSentences(s string) (n int) {
+	state := -1
+	var sentence string
+	for len(s) > 0 {
+		sentence, s, state = uniseg.FirstSentenceInString(s, state)
+		if strings.TrimSpace(sentence) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// Diff compares a and b line by line, or word by word when unit is "word",
+// and returns the changes grouped into hunks.
+func (service) Diff(a, b, unit string, context int) ([]Hunk, error) {
+	var split func(string) []string
+	switch strings.ToLower(unit) {
+	case "", "line", "lines":
+		split = splitLines
+	case "word", "words":
+		split = splitWords
+	default:
+		return nil, ErrDiffUnit
+	}
+	if context < 0 {
+		context = 0
+	}
+	at, bt := split(a), split(b)
+	edits, err := myers(at, bt)
+	if err != nil {
+		return nil, err
+	}
+	return hunks(edits, at, bt, context), nil
+}
+
+func (service) Similarity(a, b string) (Similarity, error) {
+	if err := checkSimilarityLen(a, b); err != nil {
+		return Similarity{}, err
+	}
+	ar, br := []rune(a), []rune(b)
+	lev := levenshtein(ar, br)
+	return Similarity{
+		Levenshtein:        lev,
+		DamerauLevenshtein: damerauLevenshtein(ar, br),
+		JaroWinkler:        jaroWinkler(ar, br),
+		Normalized:         normalizedSimilarity(lev, ar, br),
+		Soundex:            []string{soundex(a), soundex(b)},
+		Metaphone:          []string{metaphone(a), metaphone(b)},
+	}, nil
+}
+
+// Match scores every candidate against query with the given metric and
+// returns the best limit of them, highest score first. Scores are in [0, 1]
+// and limit <= 0 returns them all. It stops early once ctx is done.
+func (service) Match(ctx context.Context, query string, candidates []string, metric string, limit int) ([]Match, error) {
+	var score func(a, b []rune) float64
+	switch strings.ToLower(metric) {
+	case "", "jaro_winkler":
+		score = jaroWinkler
+	case "levenshtein":
+		score = func(a, b []rune) float64 {
+			return normalizedSimilarity(levenshtein(a, b), a, b)
+		}
+	case "damerau_levenshtein":
+		score = func(a, b []rune) float64 {
+			return normalizedSimilarity(damerauLevenshtein(a, b), a, b)
+		}
+	default:
+		return nil, ErrMetric
+	}
+	if len(candidates) > maxCandidates {
+		return nil, ErrTooManyCandidates
+	}
+	if err := checkSimilarityLen(query); err != nil {
+		return nil, err
+	}
+	if err := checkSimilarityLen(candidates...); err != nil {
+		return nil, err
+	}
+
+	q := []rune(query)
+	matches := make([]Match, len(candidates))
+	for i, c := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		matches[i] = Match{c, score(q, []rune(c))}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if limit > 0 && limit < len(matches) {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+func (service) Encode(s, scheme string) (string, error) {
+	c, ok := codecs[strings.ToLower(scheme)]
+	if !ok {
+		return "", ErrScheme
+	}
+	return c.encode(s), nil
+}
+
+// Decode reverses Encode. Malformed input is reported as a *DecodeError
+// with the offset of the first bad byte.
+func (service) Decode(s, scheme string) ([]byte, error) {
+	c, ok := codecs[strings.ToLower(scheme)]
+	if !ok {
+		return nil, ErrScheme
+	}
+	return c.decode(s)
+}
+
+func (service) RegexTest(pattern, s string) (bool, error) {
+	re, err := compileRegex(pattern, s)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(s), nil
+}
+
+// RegexFind returns up to limit matches. Without a limit it returns
+// ErrTooManyMatches rather than silently truncating past the server maximum.
+func (service) RegexFind(pattern, s string, limit int) ([]RegexMatch, error) {
+	re, err := compileRegex(pattern, s)
+	if err != nil {
+		return nil, err
+	}
+	n := limit
+	if n <= 0 || n > maxRegexMatches {
+		n = maxRegexMatches + 1
+	}
+	idx := re.FindAllStringSubmatchIndex(s, n)
+	if len(idx) > maxRegexMatches {
+		return nil, ErrTooManyMatches
+	}
+	return regexMatches(re, s, idx), nil
+}
+
+// RegexReplace replaces every match, expanding $1 and ${name} in template.
+// It fails rather than return more than maxRegexMatches replacements or a
+// result over maxReplaceOutput bytes.
+func (service) RegexReplace(pattern, s, template string) (string, error) {
+	re, err := compileRegex(pattern, s)
+	if err != nil {
+		return "", err
+	}
+	return regexReplace(re, s, template)
+}
+
+func parseLocale(locale string) (language.Tag, error) {
+	if locale == "" {
+		return language.Und, nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return language.Und, ErrLocale
+	}
+	return tag, nil
 }
 
 func NewService() Service {
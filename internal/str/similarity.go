@@ -0,0 +1,356 @@
+package str
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxSimilarityRunes bounds each string compared, since
+	// damerauLevenshtein fills a table of the product of their lengths.
+	maxSimilarityRunes = 2000
+	// maxCandidates bounds the strings a query is matched against.
+	maxCandidates = 1000
+)
+
+func checkSimilarityLen(ss ...string) error {
+	for _, s := range ss {
+		if utf8.RuneCountInString(s) > maxSimilarityRunes {
+			return ErrCompareTooLong
+		}
+	}
+	return nil
+}
+
+// Similarity holds edit distances, similarity scores and phonetic codes for a
+// pair of strings. Distances count runes.
+type Similarity struct {
+	Levenshtein        int      `json:"levenshtein"`
+	DamerauLevenshtein int      `json:"damerau_levenshtein"`
+	JaroWinkler        float64  `json:"jaro_winkler"`
+	Normalized         float64  `json:"normalized"`
+	Soundex            []string `json:"soundex"`
+	Metaphone          []string `json:"metaphone"`
+}
+
+// Match is a candidate scored against a query.
+type Match struct {
+	Candidate string  `json:"candidate"`
+	Score     float64 `json:"score"`
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// damerauLevenshtein is the unrestricted distance, allowing transposed
+// runes to be edited again after the transposition.
+func damerauLevenshtein(a, b []rune) int {
+	inf := len(a) + len(b)
+	d := make([][]int, len(a)+2)
+	for i := range d {
+		d[i] = make([]int, len(b)+2)
+	}
+	d[0][0] = inf
+	for i := 0; i <= len(a); i++ {
+		d[i+1][0] = inf
+		d[i+1][1] = i
+	}
+	for j := 0; j <= len(b); j++ {
+		d[0][j+1] = inf
+		d[1][j+1] = j
+	}
+
+	last := make(map[rune]int)
+	for i := 1; i <= len(a); i++ {
+		db := 0
+		for j := 1; j <= len(b); j++ {
+			i1 := last[b[j-1]]
+			j1 := db
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+				db = j
+			}
+			d[i+1][j+1] = min3(
+				d[i][j]+cost,
+				d[i+1][j]+1,
+				d[i][j+1]+1,
+			)
+			if t := d[i1][j1] + (i - i1 - 1) + 1 + (j - j1 - 1); t < d[i+1][j+1] {
+				d[i+1][j+1] = t
+			}
+		}
+		last[a[i-1]] = i
+	}
+	return d[len(a)+1][len(b)+1]
+}
+
+func jaro(a, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	window := max(len(a), len(b))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	am := make([]bool, len(a))
+	bm := make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		lo, hi := i-window, i+window+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(b) {
+			hi = len(b)
+		}
+		for j := lo; j < hi; j++ {
+			if !bm[j] && a[i] == b[j] {
+				am[i], bm[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range a {
+		if !am[i] {
+			continue
+		}
+		for !bm[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// jaroWinkler boosts the Jaro score for a common prefix of up to four runes.
+func jaroWinkler(a, b []rune) float64 {
+	j := jaro(a, b)
+	prefix := 0
+	for prefix < 4 && prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	return j + float64(prefix)*0.1*(1-j)
+}
+
+// normalizedSimilarity scales a distance into [0, 1] by the longer length.
+func normalizedSimilarity(distance int, a, b []rune) float64 {
+	n := max(len(a), len(b))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(n)
+}
+
+// asciiLetters uppercases s and keeps only the letters A-Z, which is all
+// the phonetic algorithms understand.
+func asciiLetters(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToUpper(r)
+		if r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+var soundexCodes = [26]byte{
+	'0', '1', '2', '3', '0', '1', '2', '0', '0', '2', '2', '4', '5',
+	'5', '0', '1', '2', '6', '2', '3', '0', '1', '0', '2', '0', '2',
+}
+
+// soundex returns the American Soundex code of s, or "" if s has no letters.
+func soundex(s string) string {
+	s = asciiLetters(s)
+	if s == "" {
+		return ""
+	}
+	code := []byte{s[0]}
+	last := soundexCodes[s[0]-'A']
+	for i := 1; i < len(s) && len(code) < 4; i++ {
+		c := s[i]
+		d := soundexCodes[c-'A']
+		if d != '0' && d != last {
+			code = append(code, d)
+		}
+		// H and W do not separate letters with the same code; vowels do.
+		if c != 'H' && c != 'W' {
+			last = d
+		}
+	}
+	for len(code) < 4 {
+		code = append(code, '0')
+	}
+	return string(code)
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("AEIOU", c) >= 0
+}
+
+// metaphone returns the original Metaphone key of s. "0" stands for the
+// "th" sound and "X" for "sh".
+func metaphone(s string) string {
+	w := asciiLetters(s)
+	if w == "" {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(w, "AE"), strings.HasPrefix(w, "GN"),
+		strings.HasPrefix(w, "KN"), strings.HasPrefix(w, "PN"),
+		strings.HasPrefix(w, "WR"):
+		w = w[1:]
+	case w[0] == 'X':
+		w = "S" + w[1:]
+	case strings.HasPrefix(w, "WH"):
+		w = "W" + w[2:]
+	}
+
+	at := func(i int) byte {
+		if i < 0 || i >= len(w) {
+			return 0
+		}
+		return w[i]
+	}
+	var key strings.Builder
+	for i := 0; i < len(w); i++ {
+		c := w[i]
+		if c == at(i-1) && c != 'C' {
+			continue
+		}
+		next, prev := at(i+1), at(i-1)
+		switch c {
+		case 'A', 'E', 'I', 'O', 'U':
+			if i == 0 {
+				key.WriteByte(c)
+			}
+		case 'B':
+			if !(prev == 'M' && i == len(w)-1) {
+				key.WriteByte('B')
+			}
+		case 'C':
+			switch {
+			case next == 'I' && at(i+2) == 'A', next == 'H' && prev != 'S':
+				key.WriteByte('X')
+			case next == 'I' || next == 'E' || next == 'Y':
+				if prev != 'S' {
+					key.WriteByte('S')
+				}
+			default:
+				key.WriteByte('K')
+			}
+		case 'D':
+			if next == 'G' && strings.IndexByte("EIY", at(i+2)) >= 0 {
+				key.WriteByte('J')
+			} else {
+				key.WriteByte('T')
+			}
+		case 'G':
+			switch {
+			case next == 'H' && i+2 < len(w) && !isVowel(at(i+2)):
+			case next == 'N' && (i+2 == len(w) || w[i+1:] == "NED"):
+			case prev == 'D' && strings.IndexByte("EIY", next) >= 0:
+			case strings.IndexByte("EIY", next) >= 0 && prev != 'G':
+				key.WriteByte('J')
+			default:
+				key.WriteByte('K')
+			}
+		case 'H':
+			if strings.IndexByte("CSPTG", prev) >= 0 {
+				break
+			}
+			if isVowel(prev) && !isVowel(next) {
+				break
+			}
+			key.WriteByte('H')
+		case 'K':
+			if prev != 'C' {
+				key.WriteByte('K')
+			}
+		case 'P':
+			if next == 'H' {
+				key.WriteByte('F')
+			} else {
+				key.WriteByte('P')
+			}
+		case 'Q':
+			key.WriteByte('K')
+		case 'S':
+			if next == 'H' || (next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A')) {
+				key.WriteByte('X')
+			} else {
+				key.WriteByte('S')
+			}
+		case 'T':
+			switch {
+			case next == 'I' && (at(i+2) == 'O' || at(i+2) == 'A'):
+				key.WriteByte('X')
+			case next == 'H':
+				key.WriteByte('0')
+			case next == 'C' && at(i+2) == 'H':
+			default:
+				key.WriteByte('T')
+			}
+		case 'V':
+			key.WriteByte('F')
+		case 'W', 'Y':
+			if isVowel(next) {
+				key.WriteByte(c)
+			}
+		case 'X':
+			key.WriteString("KS")
+		case 'Z':
+			key.WriteByte('S')
+		default:
+			key.WriteByte(c)
+		}
+	}
+	return key.String()
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
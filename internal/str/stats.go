@@ -0,0 +1,130 @@
+package str
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/cases"
+)
+
+// TextStats summarises the words and readability of a text. Readability uses
+// the Flesch formulas with an English syllable heuristic, so it is only
+// meaningful for English text.
+type TextStats struct {
+	Words             int         `json:"words"`
+	UniqueWords       int         `json:"unique_words"`
+	AverageWordLength float64     `json:"average_word_length"`
+	Sentences         int         `json:"sentences"`
+	Entropy           float64     `json:"entropy"`
+	ReadingEase       float64     `json:"flesch_reading_ease"`
+	GradeLevel        float64     `json:"flesch_kincaid_grade"`
+	TopWords          []WordCount `json:"top_words"`
+}
+
+type WordCount struct {
+	Word  string `json:"word"`
+	Count int    `json:"count"`
+}
+
+// words returns the Unicode word segments of s that contain at least one
+// letter or digit.
+func words(s string) []string {
+	var ws []string
+	state := -1
+	var word string
+	for len(s) > 0 {
+		word, s, state = uniseg.FirstWordInString(s, state)
+		if strings.IndexFunc(word, isWordRune) >= 0 {
+			ws = append(ws, word)
+		}
+	}
+	return ws
+}
+
+// entropy is the Shannon entropy of the runes of s in bits per rune.
+func entropy(s string) float64 {
+	freq := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		freq[r]++
+		n++
+	}
+	var h float64
+	for _, c := range freq {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// syllables estimates English syllables by counting vowel groups, less a
+// silent final "e".
+func syllables(word string) int {
+	w := strings.ToLower(word)
+	n := 0
+	prevVowel := false
+	for _, r := range w {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !prevVowel {
+			n++
+		}
+		prevVowel = vowel
+	}
+	if strings.HasSuffix(w, "e") && !strings.HasSuffix(w, "le") && n > 1 {
+		n--
+	}
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+func textStats(s string, top int) TextStats {
+	ws := words(s)
+	stats := TextStats{
+		Words:     len(ws),
+		Sentences: countSentences(s),
+		Entropy:   entropy(s),
+	}
+	if len(ws) == 0 {
+		return stats
+	}
+
+	fold := cases.Fold()
+	freq := make(map[string]int)
+	letters, sylls := 0, 0
+	for _, w := range ws {
+		freq[fold.String(w)]++
+		letters += utf8.RuneCountInString(w)
+		sylls += syllables(w)
+	}
+	stats.UniqueWords = len(freq)
+	stats.AverageWordLength = float64(letters) / float64(len(ws))
+
+	sentences := stats.Sentences
+	if sentences == 0 {
+		sentences = 1
+	}
+	wps := float64(len(ws)) / float64(sentences)
+	spw := float64(sylls) / float64(len(ws))
+	stats.ReadingEase = 206.835 - 1.015*wps - 84.6*spw
+	stats.GradeLevel = 0.39*wps + 11.8*spw - 15.59
+
+	for w, c := range freq {
+		stats.TopWords = append(stats.TopWords, WordCount{w, c})
+	}
+	sort.Slice(stats.TopWords, func(i, j int) bool {
+		a, b := stats.TopWords[i], stats.TopWords[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Word < b.Word
+	})
+	if top > 0 && top < len(stats.TopWords) {
+		stats.TopWords = stats.TopWords[:top]
+	}
+	return stats
+}
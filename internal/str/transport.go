@@ -3,7 +3,9 @@ package str
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
+	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
@@ -16,20 +18,189 @@ func MakeHandler(ss Service) http.Handler {
 		encodeResponse,
 	)
 
+	lowercaseHandler := kithttp.NewServer(
+		makeLowercaseEndpoint(ss),
+		decodeTransformRequest,
+		encodeResponse,
+	)
+
+	titleHandler := kithttp.NewServer(
+		makeTitleEndpoint(ss),
+		decodeTransformRequest,
+		encodeResponse,
+	)
+
+	foldHandler := kithttp.NewServer(
+		makeFoldEndpoint(ss),
+		decodeTransformRequest,
+		encodeResponse,
+	)
+
+	normalizeHandler := kithttp.NewServer(
+		makeNormalizeEndpoint(ss),
+		decodeNormalizeRequest,
+		encodeResponse,
+	)
+
+	reverseHandler := kithttp.NewServer(
+		makeReverseEndpoint(ss),
+		decodeTransformRequest,
+		encodeResponse,
+	)
+
+	caseHandler := kithttp.NewServer(
+		makeCaseEndpoint(ss),
+		decodeCaseRequest,
+		encodeResponse,
+	)
+
+	wrapHandler := kithttp.NewServer(
+		makeWrapEndpoint(ss),
+		decodeWrapRequest,
+		encodeResponse,
+	)
+
 	countHandler := kithttp.NewServer(
 		makeCountEndpoint(ss),
 		decodeCountRequest,
 		encodeResponse,
 	)
 
+	pipelineHandler := kithttp.NewServer(
+		makePipelineEndpoint(ss),
+		decodePipelineRequest,
+		encodeResponse,
+	)
+
+	diffHandler := kithttp.NewServer(
+		makeDiffEndpoint(ss),
+		decodeDiffRequest,
+		encodeResponse,
+	)
+
+	similarityHandler := kithttp.NewServer(
+		makeSimilarityEndpoint(ss),
+		decodeSimilarityRequest,
+		encodeResponse,
+	)
+
+	matchHandler := kithttp.NewServer(
+		makeMatchEndpoint(ss),
+		decodeMatchRequest,
+		encodeResponse,
+	)
+
+	encodeHandler := kithttp.NewServer(
+		makeEncodeEndpoint(ss),
+		decodeCodecRequest,
+		encodeResponse,
+	)
+
+	decodeHandler := kithttp.NewServer(
+		makeDecodeEndpoint(ss),
+		decodeCodecRequest,
+		encodeResponse,
+	)
+
+	regexHandler := kithttp.NewServer(
+		makeRegexEndpoint(ss),
+		decodeRegexRequest,
+		encodeResponse,
+	)
+
+	statsHandler := kithttp.NewServer(
+		makeStatsEndpoint(ss),
+		decodeStatsRequest,
+		encodeResponse,
+	)
+
+	languageHandler := kithttp.NewServer(
+		makeLanguageEndpoint(ss),
+		decodeLanguageRequest,
+		encodeResponse,
+	)
+
 	r := mux.NewRouter()
 
+	r.Path("/string/uppercase").
+		HeadersRegexp("Content-Type", "^text/plain").
+		Handler(makeUppercaseStreamHandler(ss)).
+		Methods("POST")
 	r.Path("/string/uppercase").Handler(uppercaseHandler).Methods("POST")
+	r.Path("/string/lowercase").Handler(lowercaseHandler).Methods("POST")
+	r.Path("/string/title").Handler(titleHandler).Methods("POST")
+	r.Path("/string/fold").Handler(foldHandler).Methods("POST")
+	r.Path("/string/normalize").Handler(normalizeHandler).Methods("POST")
+	r.Path("/string/reverse").Handler(reverseHandler).Methods("POST")
+	r.Path("/string/case").Handler(caseHandler).Methods("POST")
+	r.Path("/string/wrap").Handler(wrapHandler).Methods("POST")
 	r.Path("/string/count").Handler(countHandler).Methods("POST")
+	r.Path("/string/stats").Handler(statsHandler).Methods("POST")
+	r.Path("/string/language").Handler(languageHandler).Methods("POST")
+	r.Path("/string/pipeline").Handler(pipelineHandler).Methods("POST")
+	r.Path("/string/diff").Handler(diffHandler).Methods("POST")
+	r.Path("/string/similarity").Handler(similarityHandler).Methods("POST")
+	r.Path("/string/match").Handler(matchHandler).Methods("POST")
+	r.Path("/string/encode").Handler(encodeHandler).Methods("POST")
+	r.Path("/string/decode").Handler(decodeHandler).Methods("POST")
+	r.Path("/string/regex").Handler(regexHandler).Methods("POST")
 
 	return r
 }
 
+// streamTimeout is how long a streaming request may go without reading or
+// writing a chunk. It replaces the server's whole-request timeouts, which
+// would cut off large bodies.
+const streamTimeout = 10 * time.Second
+
+// makeUppercaseStreamHandler serves text/plain bodies as a chunked text/plain
+// response, flushing after every transformed chunk. The locale is taken from
+// the query string.
+func makeUppercaseStreamHandler(ss Service) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		n, err := ss.UppercaseStream(
+			&streamWriter{w, rc},
+			&streamReader{r.Body, rc},
+			r.URL.Query().Get("locale"),
+		)
+		if err != nil {
+			if n == 0 {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			// the status line is already sent, so abort the response to
+			// keep the client from mistaking it for a complete one
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
+
+type streamReader struct {
+	r  io.Reader
+	rc *http.ResponseController
+}
+
+func (sr *streamReader) Read(p []byte) (int, error) {
+	sr.rc.SetReadDeadline(time.Now().Add(streamTimeout))
+	return sr.r.Read(p)
+}
+
+type streamWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	sw.rc.SetWriteDeadline(time.Now().Add(streamTimeout))
+	n, err := sw.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, sw.rc.Flush()
+}
+
 func decodeUppercaseRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var request uppercaseRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -38,6 +209,38 @@ func decodeUppercaseRequest(_ context.Context, r *http.Request) (interface{}, er
 	return request, nil
 }
 
+func decodeTransformRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request transformRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeNormalizeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request normalizeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeCaseRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request caseRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeWrapRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request wrapRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
 func decodeCountRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	var request countRequest
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -46,6 +249,70 @@ func decodeCountRequest(_ context.Context, r *http.Request) (interface{}, error)
 	return request, nil
 }
 
+func decodeStatsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request statsRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeLanguageRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request languageRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodePipelineRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request pipelineRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeDiffRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request diffRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeSimilarityRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request similarityRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeMatchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request matchRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeCodecRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request codecRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeRegexRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request regexRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	return json.NewEncoder(w).Encode(response)
 }
@@ -0,0 +1,122 @@
+package str
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// maxWrapWidth bounds the width of wrapped lines, since short lines are
+// padded out to it when aligned.
+const maxWrapWidth = 1000
+
+// splitWidth breaks a word wider than width into pieces of at most width
+// columns, never splitting a grapheme cluster.
+func splitWidth(word string, width int) []string {
+	var pieces []string
+	var cur strings.Builder
+	curWidth := 0
+	g := uniseg.NewGraphemes(word)
+	for g.Next() {
+		w := g.Width()
+		if curWidth+w > width && curWidth > 0 {
+			pieces = append(pieces, cur.String())
+			cur.Reset()
+			curWidth = 0
+		}
+		cur.WriteString(g.Str())
+		curWidth += w
+	}
+	if cur.Len() > 0 {
+		pieces = append(pieces, cur.String())
+	}
+	return pieces
+}
+
+// fill greedily packs words into lines, the first first columns wide and
+// the rest rest columns wide.
+func fill(words []string, first, rest int) [][]string {
+	narrowest := first
+	if rest < narrowest {
+		narrowest = rest
+	}
+	var split []string
+	for _, w := range words {
+		if uniseg.StringWidth(w) > narrowest {
+			split = append(split, splitWidth(w, narrowest)...)
+		} else {
+			split = append(split, w)
+		}
+	}
+
+	var lines [][]string
+	var line []string
+	avail, used := first, 0
+	for _, w := range split {
+		ww := uniseg.StringWidth(w)
+		if len(line) > 0 && used+1+ww > avail {
+			lines = append(lines, line)
+			line, avail, used = nil, rest, 0
+		}
+		if len(line) > 0 {
+			used++
+		}
+		line = append(line, w)
+		used += ww
+	}
+	return append(lines, line)
+}
+
+func alignLine(words []string, width int, align string, last bool) string {
+	text := strings.Join(words, " ")
+	gap := width - uniseg.StringWidth(text)
+	if gap <= 0 {
+		return text
+	}
+	switch align {
+	case "right":
+		return strings.Repeat(" ", gap) + text
+	case "center":
+		return strings.Repeat(" ", gap/2) + text
+	case "justify":
+		if last || len(words) < 2 {
+			return text
+		}
+		slots := len(words) - 1
+		var buf strings.Builder
+		for i, w := range words {
+			buf.WriteString(w)
+			if i < slots {
+				n := 1 + gap/slots
+				if i < gap%slots {
+					n++
+				}
+				buf.WriteString(strings.Repeat(" ", n))
+			}
+		}
+		return buf.String()
+	default:
+		return text
+	}
+}
+
+func wrapText(s string, width int, align string, indent, hanging int) string {
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			out = append(out, "")
+			continue
+		}
+		lines := fill(words, width-indent, width-hanging)
+		for i, lw := range lines {
+			pad := hanging
+			if i == 0 {
+				pad = indent
+			}
+			line := alignLine(lw, width-pad, align, i == len(lines)-1)
+			out = append(out, strings.Repeat(" ", pad)+line)
+		}
+	}
+	return strings.Join(out, "\n")
+}